@@ -2,9 +2,30 @@ package handler
 
 import (
     "fmt"
+    "log"
     "net/http"
+    "net/url"
+    "sync"
+
+    "adfit-oauth/config"
 )
 
+var loadConfigOnce sync.Once
+
+// 로그인 후 돌아갈 앱 주소 (oauth.redirect_targets의 기본 대상)
+func callbackTarget() string {
+    loadConfigOnce.Do(func() {
+        if err := config.LoadConfig(""); err != nil {
+            log.Printf("⚠️ 설정 파일 로드 실패, 기본 리다이렉트 대상 사용: %v", err)
+        }
+    })
+    target, err := config.RedirectTargetURL("", "tiktok")
+    if err != nil {
+        return "https://adfit.ai/#/auth/callback/tiktok"
+    }
+    return target
+}
+
 func Handler(w http.ResponseWriter, r *http.Request) {
     // CORS 설정
     w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -19,11 +40,12 @@ func Handler(w http.ResponseWriter, r *http.Request) {
     code := r.URL.Query().Get("code")
     state := r.URL.Query().Get("state")
     
-    // Flutter 앱으로 리다이렉트
+    // Flutter 앱으로 리다이렉트 (메인 서버와 같은 redirect_targets 사용)
     clientRedirect := fmt.Sprintf(
-        "https://adfit.ai/auth/callback/tiktok?code=%s&state=%s",
-        code,
-        state,
+        "%s?code=%s&state=%s",
+        callbackTarget(),
+        url.QueryEscape(code),
+        url.QueryEscape(state),
     )
     
     http.Redirect(w, r, clientRedirect, http.StatusTemporaryRedirect)
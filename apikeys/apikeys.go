@@ -0,0 +1,197 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+)
+
+// 키 형식: adk_<prefix>_<secret>
+const keyPrefix = "adk_"
+
+// last_used_at 갱신 최소 간격 (요청마다 쓰지 않도록)
+const lastUsedInterval = time.Minute
+
+// Scopes 발급 가능한 권한
+var Scopes = []string{
+	"stats:update",  // /api/stats/update/*
+	"admin:read",    // /api/admin 조회 API
+	"admin:trigger", // /api/admin/trigger/*
+	"admin:cleanup", // /api/admin/cleanup/*
+}
+
+var (
+	ErrNotAPIKey    = errors.New("not an api key")
+	ErrInvalidKey   = errors.New("invalid api key")
+	ErrExpiredKey   = errors.New("api key expired")
+	ErrRevokedKey   = errors.New("api key revoked")
+	ErrInvalidScope = errors.New("invalid api key scope")
+)
+
+// Store SQLite API 키 저장소
+type Store struct {
+	DB *gorm.DB
+	// LegacyToken 기존 고정 토큰 (stats.update_token), stats:update 권한만 부여
+	LegacyToken string
+}
+
+func NewStore(db *gorm.DB, legacyToken string) *Store {
+	return &Store{DB: db, LegacyToken: legacyToken}
+}
+
+// IsAPIKey API 키 형식인지 (또는 기존 고정 토큰인지)
+func (s *Store) IsAPIKey(key string) bool {
+	return strings.HasPrefix(key, keyPrefix) || s.isLegacy(key)
+}
+
+// Mint 새 API 키 발급 (원문은 이때만 반환)
+func (s *Store) Mint(name string, scopes []string, expiresAt *time.Time, createdBy string) (string, *models.APIKey, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, errors.New("name is required")
+	}
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !validScope(scope) {
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+
+	prefix, err := randomString(6)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+	plaintext := keyPrefix + prefix + "_" + secret
+
+	key := models.APIKey{
+		Name:      strings.TrimSpace(name),
+		Prefix:    keyPrefix + prefix,
+		KeyHash:   hashKey(plaintext),
+		Scopes:    strings.Join(scopes, " "),
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.DB.Create(&key).Error; err != nil {
+		return "", nil, err
+	}
+	return plaintext, &key, nil
+}
+
+// Authenticate API 키 확인 (폐기/만료 거부, 마지막 사용 시각 기록)
+func (s *Store) Authenticate(key, clientIP string) (*models.APIKey, error) {
+	if s.isLegacy(key) {
+		return &models.APIKey{Name: "legacy stats.update_token", Prefix: "legacy", Scopes: "stats:update"}, nil
+	}
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, ErrNotAPIKey
+	}
+
+	var stored models.APIKey
+	if err := s.DB.Where("key_hash = ?", hashKey(key)).First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case stored.RevokedAt != nil:
+		return nil, ErrRevokedKey
+	case stored.ExpiresAt != nil && now.After(*stored.ExpiresAt):
+		return nil, ErrExpiredKey
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > lastUsedInterval || stored.LastUsedIP != clientIP {
+		s.DB.Model(&stored).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": clientIP})
+		stored.LastUsedAt = &now
+		stored.LastUsedIP = clientIP
+	}
+	return &stored, nil
+}
+
+// List 발급된 API 키 목록 (폐기된 키 포함)
+func (s *Store) List() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := s.DB.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke API 키 폐기
+func (s *Store) Revoke(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := s.DB.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := s.DB.Model(&key).Update("revoked_at", now).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &key, nil
+}
+
+func (s *Store) isLegacy(key string) bool {
+	return s.LegacyToken != "" && subtle.ConstantTimeCompare([]byte(key), []byte(s.LegacyToken)) == 1
+}
+
+func validScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	// '_'는 구분자로 사용하므로 제외
+	return strings.ReplaceAll(base64.RawURLEncoding.EncodeToString(b), "_", "-"), nil
+}
+
+// 전역 Store (미들웨어에서 사용)
+var (
+	mu      sync.RWMutex
+	current *Store
+)
+
+// SetDefault 전역 Store 설정
+func SetDefault(s *Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// Default 전역 Store (설정되지 않았으면 nil → API 키 인증 비활성화)
+func Default() *Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
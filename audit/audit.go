@@ -0,0 +1,268 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+)
+
+// 핸들러 → 감사 미들웨어 전달용 컨텍스트 키
+const (
+	affectedKey = "audit_affected"
+	paramsKey   = "audit_params"
+	errorKey    = "audit_error"
+)
+
+// 파라미터 값을 기록하지 않는 키 (부분 일치)
+var sensitiveParams = []string{"password", "secret", "token", "key"}
+
+// Store SQLite 감사 기록 저장소
+type Store struct {
+	DB *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{DB: db}
+}
+
+// Migrate 테이블 생성 + 수정/삭제 거부 트리거 (추가만 가능)
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+		return err
+	}
+	for _, op := range []string{"UPDATE", "DELETE"} {
+		stmt := fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS audit_logs_no_%s BEFORE %s ON audit_logs
+BEGIN SELECT RAISE(ABORT, 'audit_logs is append-only'); END`, strings.ToLower(op), op)
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record 감사 기록 추가
+func (s *Store) Record(entry *models.AuditLog) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	return s.DB.Create(entry).Error
+}
+
+// Filter 조회 조건 (빈 값은 조건 없음)
+type Filter struct {
+	Actor     string
+	Action    string // 부분 일치
+	Result    string
+	RequestID string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
+
+// 조회 최대 건수
+const MaxLimit = 1000
+
+// Export에서 한 번에 읽는 건수
+const exportBatch = 500
+
+// Query 최신순 조회 (전체 건수 포함)
+func (s *Store) Query(f Filter) ([]models.AuditLog, int64, error) {
+	query := s.filtered(f)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if f.Limit <= 0 || f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+	var logs []models.AuditLog
+	err := query.Order("id DESC").Limit(f.Limit).Offset(f.Offset).Find(&logs).Error
+	return logs, total, err
+}
+
+// Count 조건에 맞는 전체 건수
+func (s *Store) Count(f Filter) (int64, error) {
+	var total int64
+	err := s.filtered(f).Count(&total).Error
+	return total, err
+}
+
+// Export 조건에 맞는 기록을 최신순으로 나누어 fn에 전달 (CSV 내보내기용, MaxLimit 없음)
+//
+// f.Limit이 0이면 전체, f.Offset부터 시작합니다. 중간에 기록이 추가되어도 id 기준으로 이어 읽습니다.
+func (s *Store) Export(f Filter, fn func([]models.AuditLog) error) error {
+	remaining := f.Limit
+	var lastID uint
+	for {
+		size := exportBatch
+		if f.Limit > 0 {
+			if remaining <= 0 {
+				return nil
+			}
+			if remaining < size {
+				size = remaining
+			}
+		}
+
+		query := s.filtered(f)
+		if lastID > 0 {
+			query = query.Where("id < ?", lastID)
+		} else if f.Offset > 0 {
+			query = query.Offset(f.Offset)
+		}
+		var logs []models.AuditLog
+		if err := query.Order("id DESC").Limit(size).Find(&logs).Error; err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+		lastID = logs[len(logs)-1].ID
+		remaining -= len(logs)
+		if len(logs) < size {
+			return nil
+		}
+	}
+}
+
+// 조회 조건 적용
+func (s *Store) filtered(f Filter) *gorm.DB {
+	query := s.DB.Model(&models.AuditLog{})
+	if f.Actor != "" {
+		query = query.Where("actor = ?", f.Actor)
+	}
+	if f.Action != "" {
+		query = query.Where("action LIKE ?", "%"+f.Action+"%")
+	}
+	if f.Result != "" {
+		query = query.Where("result = ?", f.Result)
+	}
+	if f.RequestID != "" {
+		query = query.Where("request_id = ?", f.RequestID)
+	}
+	if !f.From.IsZero() {
+		query = query.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		query = query.Where("created_at < ?", f.To)
+	}
+	return query
+}
+
+// SetAffected 삭제/변경된 레코드 수 기록 (핸들러에서 호출)
+func SetAffected(c *gin.Context, n int64) {
+	c.Set(affectedKey, n)
+}
+
+// AddParam 감사 기록에 남길 값 추가 (핸들러에서 호출)
+func AddParam(c *gin.Context, key string, value interface{}) {
+	params, _ := c.Get(paramsKey)
+	m, ok := params.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+		c.Set(paramsKey, m)
+	}
+	m[key] = value
+}
+
+// SetError 실패 사유 기록 (핸들러에서 호출)
+func SetError(c *gin.Context, err error) {
+	if err != nil {
+		c.Set(errorKey, err.Error())
+	}
+}
+
+// Entry 요청 정보로 감사 기록 생성 (actor/role은 호출하는 쪽에서 채움)
+func Entry(c *gin.Context) *models.AuditLog {
+	params := map[string]interface{}{}
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	for key, values := range c.Request.URL.Query() {
+		if isSensitive(key) {
+			params[key] = "***"
+			continue
+		}
+		if len(values) == 1 {
+			params[key] = values[0]
+		} else {
+			params[key] = values
+		}
+	}
+	if extra, ok := c.Get(paramsKey); ok {
+		for key, value := range extra.(map[string]interface{}) {
+			params[key] = value
+		}
+	}
+	encoded, _ := json.Marshal(params)
+
+	status := c.Writer.Status()
+	entry := &models.AuditLog{
+		RequestID: c.GetString("request_id"),
+		Action:    c.Request.Method + " " + c.FullPath(),
+		Endpoint:  c.Request.URL.Path,
+		Params:    string(encoded),
+		Status:    status,
+		Error:     c.GetString(errorKey),
+		ClientIP:  c.ClientIP(),
+	}
+	if n, ok := c.Get(affectedKey); ok {
+		entry.Affected, _ = n.(int64)
+	}
+
+	switch {
+	case status == 401 || status == 403:
+		entry.Result = models.AuditResultDenied
+	case status >= 400:
+		entry.Result = models.AuditResultFailure
+	default:
+		entry.Result = models.AuditResultSuccess
+	}
+	if entry.Error == "" && len(c.Errors) > 0 {
+		entry.Error = c.Errors.String()
+	}
+	return entry
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveParams {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// 전역 Store (미들웨어에서 사용)
+var (
+	mu      sync.RWMutex
+	current *Store
+)
+
+// SetDefault 전역 Store 설정
+func SetDefault(s *Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// Default 전역 Store (설정되지 않았으면 nil → 감사 기록 비활성화)
+func Default() *Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"adfit-oauth/config"
+	"adfit-oauth/services"
+)
+
+// 관리 명령 실행
+func runCommand(args []string) error {
+	switch args[0] {
+	case "reencrypt-tokens":
+		// 키 교체 후 모든 토큰을 활성 키로 다시 암호화
+		db, err := initDatabase()
+		if err != nil {
+			return fmt.Errorf("데이터베이스 초기화 실패: %v", err)
+		}
+		count, err := services.ReencryptTokens(db, false)
+		if err != nil {
+			return fmt.Errorf("토큰 재암호화 실패: %v", err)
+		}
+		log.Printf("✅ 토큰 %d개 재암호화 완료", count)
+		return nil
+	case "create-admin":
+		// 관리자 계정 생성: create-admin <username> <role> (비밀번호는 ADMIN_PASSWORD 환경 변수)
+		if len(args) != 3 {
+			return fmt.Errorf("사용법: create-admin <username> <viewer|operator|superadmin>")
+		}
+		password := os.Getenv("ADMIN_PASSWORD")
+		if password == "" {
+			return fmt.Errorf("ADMIN_PASSWORD 환경 변수가 필요합니다")
+		}
+		db, err := initDatabase()
+		if err != nil {
+			return fmt.Errorf("데이터베이스 초기화 실패: %v", err)
+		}
+		account, err := services.NewAdminAccountService(db).Create(args[1], password, args[2])
+		if err != nil {
+			return fmt.Errorf("관리자 계정 생성 실패: %v", err)
+		}
+		log.Printf("✅ 관리자 계정 생성 완료: %s (%s)", account.Username, account.Role)
+		return nil
+	case "validate-config":
+		// 설정 검증만 실행 (배포 전 확인용, 문제가 있으면 종료 코드 1)
+		cfg := config.Current()
+		if err := config.Validate(cfg); err != nil {
+			return err
+		}
+		log.Printf("✅ 설정 검증 통과 (환경: %s)", cfg.App.Environment)
+		return nil
+	case "config":
+		// 환경별 최종 설정 출력: config print --env=production (비밀 값은 가림)
+		if len(args) < 2 || args[1] != "print" {
+			return fmt.Errorf("사용법: config print [--env=<환경>] [--config=<설정 파일>]")
+		}
+		return printConfig(args[2:])
+	default:
+		return fmt.Errorf("알 수 없는 명령: %s (사용 가능: reencrypt-tokens, create-admin, validate-config, config print)", args[0])
+	}
+}
+
+// environments.<env> 오버라이드와 환경변수까지 적용된 설정을 YAML로 출력
+func printConfig(args []string) error {
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	env := flags.String("env", "", "환경 (기본: ENVIRONMENT 또는 app.environment)")
+	path := flags.String("config", "config/app_config.yaml", "설정 파일 경로")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*path, *env)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Environments[cfg.App.Environment]; !ok {
+		log.Printf("⚠️ environments.%s 블록이 없습니다 (기본 설정만 적용)", cfg.App.Environment)
+	}
+	if err := config.Validate(cfg); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+
+	out, err := cfg.MaskedYAML()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
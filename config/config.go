@@ -24,6 +24,9 @@ type AppConfig struct {
 	Security     SecurityConfig       `yaml:"security"`
 	Features     FeatureFlags         `yaml:"features"`
 	Environments map[string]AppConfig `yaml:"environments"`
+
+	// 비밀 값 참조 해석 실패 (Validate에서 보고)
+	secretErrors ValidationErrors
 }
 
 type AppSettings struct {
@@ -47,11 +50,26 @@ type DatabaseConfig struct {
 type FirebaseConfig struct {
 	ProjectID       string `yaml:"project_id"`
 	CredentialsPath string `yaml:"credentials_path"`
+	AuthEnabled     bool   `yaml:"auth_enabled"` // Firebase ID token으로 보호된 API 호출 허용
+	JWKSURL         string `yaml:"jwks_url"`     // ID token 서명 공개키 (비어 있으면 Google JWKS)
 }
 
 type OAuthConfig struct {
-	TikTok  OAuthProvider `yaml:"tiktok"`
-	YouTube OAuthProvider `yaml:"youtube"`
+	TikTok    OAuthProvider `yaml:"tiktok"`
+	YouTube   OAuthProvider `yaml:"youtube"`
+	Instagram OAuthProvider `yaml:"instagram"`
+	// LinkConflict 이미 다른 사용자에게 연결된 플랫폼 계정을 연결할 때: "deny"(기본) 또는 "transfer"
+	LinkConflict string `yaml:"link_conflict"`
+	// RedirectTargets 로그인 후 돌아갈 앱 주소 (이름 → 주소, 여기 등록된 것만 허용)
+	RedirectTargets map[string]RedirectTarget `yaml:"redirect_targets"`
+	// DefaultRedirectTarget 인증 요청에 redirect_target이 없을 때 사용할 이름
+	DefaultRedirectTarget string `yaml:"default_redirect_target"`
+}
+
+// RedirectTarget 로그인 후 돌아갈 앱 주소 ({platform}은 tiktok/youtube/instagram으로 치환)
+type RedirectTarget struct {
+	URL       string            `yaml:"url"`       // 예: https://adfit.ai/#/auth/callback/{platform}, adfit://oauth/{platform}
+	Platforms map[string]string `yaml:"platforms"` // 플랫폼별로 다른 주소를 쓸 때
 }
 
 type OAuthProvider struct {
@@ -61,6 +79,7 @@ type OAuthProvider struct {
 	Scopes       []string `yaml:"scopes"`
 	AuthURL      string   `yaml:"auth_url"`
 	TokenURL     string   `yaml:"token_url"`
+	APIURL       string   `yaml:"api_url"`
 	APIKey       string   `yaml:"api_key"`
 }
 
@@ -74,6 +93,8 @@ type CORSConfig struct {
 
 type StatsConfig struct {
 	UpdateToken   string `yaml:"update_token"`
+	// AllowLegacyToken true일 때만 update_token 허용 (API 키로 옮기기 전 임시로만 사용)
+	AllowLegacyToken bool `yaml:"allow_legacy_token"`
 	YouTubeAPIKey string `yaml:"youtube_api_key"`
 	BatchSize     int    `yaml:"batch_size"`
 }
@@ -91,17 +112,53 @@ type LoggingConfig struct {
 }
 
 type SecurityConfig struct {
-	JWTSecret string `yaml:"jwt_secret"`
-	TokenTTL  string `yaml:"token_ttl"`
-	RateLimit struct {
-		RequestsPerMinute int `yaml:"requests_per_minute"`
-		Burst             int `yaml:"burst"`
-	} `yaml:"rate_limit"`
+	JWTSecret  string           `yaml:"jwt_secret"`
+	TokenTTL   string           `yaml:"token_ttl"`
+	Encryption EncryptionConfig `yaml:"encryption"`
+	Session    SessionConfig    `yaml:"session"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	// TrustedProxies X-Forwarded-For를 믿을 앞단 프록시 (IP/CIDR, 비어 있으면 접속 주소만 사용)
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// RateLimitConfig 토큰 버킷 요청 제한 (기본 예산은 인증된 API에 사용자/API 키별로 적용)
+type RateLimitConfig struct {
+	Enabled           *bool           `yaml:"enabled"` // 비어 있으면 활성화
+	Backend           string          `yaml:"backend"` // 버킷 저장소 (기본 memory)
+	RequestsPerMinute int             `yaml:"requests_per_minute"`
+	Burst             int             `yaml:"burst"`
+	OAuth             RateLimitBudget `yaml:"oauth"` // 공개 OAuth/로그인 라우트 (IP별)
+	Stats             RateLimitBudget `yaml:"stats"` // 통계 업데이트/수동 실행 라우트
+}
+
+// RateLimitBudget 라우트 그룹별 예산 (0이면 제한 없음)
+type RateLimitBudget struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	Burst             int `yaml:"burst"`
+}
+
+// EncryptionConfig OAuth 토큰 암호화 (봉투 암호화 마스터 키)
+type EncryptionConfig struct {
+	ActiveKeyID string            `yaml:"active_key_id"`
+	Keys        map[string]string `yaml:"keys"` // 키 ID → base64 인코딩된 32바이트 키
+}
+
+// SessionConfig 세션 JWT / refresh token
+type SessionConfig struct {
+	Issuer      string            `yaml:"issuer"`
+	Audience    string            `yaml:"audience"`
+	AccessTTL   string            `yaml:"access_ttl"`  // access JWT 유효시간 (짧게)
+	RefreshTTL  string            `yaml:"refresh_ttl"` // refresh token 유효시간
+	ActiveKeyID string            `yaml:"active_key_id"`
+	Keys        map[string]string `yaml:"keys"`       // 키 ID(kid) → HMAC 서명 키, 교체 중에는 이전 키도 검증에 사용
+	Algorithms  []string          `yaml:"algorithms"` // 허용 서명 알고리즘 (첫 번째로 서명, 기본 HS256)
+	ClockSkew   string            `yaml:"clock_skew"` // exp/nbf/iat 검증 허용 오차 (기본 30s)
 }
 
 type FeatureFlags struct {
 	TikTokEnabled    bool `yaml:"tiktok_enabled"`
 	YouTubeEnabled   bool `yaml:"youtube_enabled"`
+	InstagramEnabled bool `yaml:"instagram_enabled"`
 	StatsEnabled     bool `yaml:"stats_enabled"`
 	CronEnabled      bool `yaml:"cron_enabled"`
 	AnalyticsEnabled bool `yaml:"analytics_enabled"`
@@ -126,110 +183,172 @@ func LoadConfig(configPath string) error {
 		return fmt.Errorf("경로 변환 실패: %v", err)
 	}
 
-	// 파일 읽기
-	data, err := os.ReadFile(absPath)
+	cfg, err := Load(absPath, "")
 	if err != nil {
-		return fmt.Errorf("설정 파일 읽기 실패 (%s): %v", absPath, err)
+		return err
 	}
 
-	// YAML 파싱
-	Config = &AppConfig{}
-	if err := yaml.Unmarshal(data, Config); err != nil {
-		return fmt.Errorf("YAML 파싱 실패: %v", err)
-	}
-
-	// 환경별 설정 오버라이드
-	applyEnvironmentOverrides()
-
-	// 환경변수 적용
-	applyEnvironmentVariables()
-
-	// OAuth 설정 초기화
-	initializeOAuthConfigs()
+	// 전역 설정 교체 + OAuth 설정 초기화
+	setCurrent(cfg, absPath)
 
 	log.Printf("✅ 설정 로드 완료: %s (환경: %s)", absPath, Config.App.Environment)
 	return nil
 }
 
-// 환경별 설정 오버라이드
-func applyEnvironmentOverrides() {
-	env := Config.App.Environment
-	if envConfig, exists := Config.Environments[env]; exists {
-		// 간단한 필드들 오버라이드
-		if envConfig.App.Debug != Config.App.Debug {
-			Config.App.Debug = envConfig.App.Debug
-		}
-		if envConfig.App.Port != "" {
-			Config.App.Port = envConfig.App.Port
-		}
-		if envConfig.Logging.Level != "" {
-			Config.Logging.Level = envConfig.Logging.Level
-		}
-		if envConfig.Logging.Format != "" {
-			Config.Logging.Format = envConfig.Logging.Format
+// Load 설정 파일 + environments.<env> 오버라이드 + 환경변수를 적용한 최종 설정 (전역 Config는 바꾸지 않음)
+// env가 비어 있으면 환경변수 ENVIRONMENT, 그것도 없으면 app.environment
+func Load(configPath, env string) (*AppConfig, error) {
+	// 파일 읽기
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("설정 파일 읽기 실패 (%s): %v", configPath, err)
+	}
+
+	// YAML 파싱
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("YAML 파싱 실패: %v", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("YAML 파싱 실패: 최상위가 맵이 아닙니다 (%s)", configPath)
+	}
+	root := doc.Content[0]
+
+	if env == "" {
+		env = os.Getenv("ENVIRONMENT")
+	}
+	if env == "" {
+		if n := mappingValue(mappingValue(root, "app"), "environment"); n != nil {
+			env = n.Value
 		}
-		if len(envConfig.CORS.AllowedOrigins) > 0 {
-			Config.CORS.AllowedOrigins = envConfig.CORS.AllowedOrigins
+	}
+
+	// 환경별 설정 오버라이드 (기본 설정 위에 깊은 병합)
+	override := environmentNode(root, env)
+	environments := mappingValue(root, "environments")
+	removeMappingKey(root, "environments")
+	merged := root
+	if override != nil {
+		if merged, err = mergeNode(root, override, "environments."+env); err != nil {
+			return nil, err
 		}
-		// Feature flags 오버라이드
-		if envConfig.Features.AnalyticsEnabled != Config.Features.AnalyticsEnabled {
-			Config.Features.AnalyticsEnabled = envConfig.Features.AnalyticsEnabled
+	}
+
+	cfg := &AppConfig{}
+	if err := merged.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("YAML 파싱 실패: %v", err)
+	}
+	if environments != nil {
+		if err := stripMergeTags(environments).Decode(&cfg.Environments); err != nil {
+			return nil, fmt.Errorf("YAML 파싱 실패 (environments): %v", err)
 		}
 	}
+	cfg.App.Environment = env
+
+	// 환경변수 적용
+	applyEnvironmentVariables(cfg)
+
+	// 비밀 값 참조 (env:, file:, sm://) 해석
+	resolveSecrets(cfg)
+	return cfg, nil
 }
 
 // 환경변수 적용
-func applyEnvironmentVariables() {
+func applyEnvironmentVariables(cfg *AppConfig) {
 	// 앱 설정
 	if port := os.Getenv("PORT"); port != "" {
-		Config.App.Port = port
-	}
-	if env := os.Getenv("ENVIRONMENT"); env != "" {
-		Config.App.Environment = env
+		cfg.App.Port = port
 	}
 
 	// TikTok OAuth 설정
 	if clientKey := os.Getenv("TIKTOK_CLIENT_KEY"); clientKey != "" {
-		Config.OAuth.TikTok.ClientID = clientKey
+		cfg.OAuth.TikTok.ClientID = clientKey
 	}
 	if clientSecret := os.Getenv("TIKTOK_CLIENT_SECRET"); clientSecret != "" {
-		Config.OAuth.TikTok.ClientSecret = clientSecret
+		cfg.OAuth.TikTok.ClientSecret = clientSecret
 	}
 	if redirectURI := os.Getenv("TIKTOK_REDIRECT_URI"); redirectURI != "" {
-		Config.OAuth.TikTok.RedirectURI = redirectURI
+		cfg.OAuth.TikTok.RedirectURI = redirectURI
 	}
 	
 	// YouTube OAuth 설정
 	if clientID := os.Getenv("YOUTUBE_CLIENT_ID"); clientID != "" {
-		Config.OAuth.YouTube.ClientID = clientID
+		cfg.OAuth.YouTube.ClientID = clientID
 	}
 	if clientSecret := os.Getenv("YOUTUBE_CLIENT_SECRET"); clientSecret != "" {
-		Config.OAuth.YouTube.ClientSecret = clientSecret
+		cfg.OAuth.YouTube.ClientSecret = clientSecret
 	}
 	
+	// Instagram OAuth 설정
+	if clientID := os.Getenv("INSTAGRAM_CLIENT_ID"); clientID != "" {
+		cfg.OAuth.Instagram.ClientID = clientID
+	}
+	if clientSecret := os.Getenv("INSTAGRAM_CLIENT_SECRET"); clientSecret != "" {
+		cfg.OAuth.Instagram.ClientSecret = clientSecret
+	}
+	if redirectURI := os.Getenv("INSTAGRAM_REDIRECT_URI"); redirectURI != "" {
+		cfg.OAuth.Instagram.RedirectURI = redirectURI
+	}
+
+	// 로그인 후 기본 리다이렉트 대상
+	if target := os.Getenv("OAUTH_REDIRECT_TARGET"); target != "" {
+		cfg.OAuth.DefaultRedirectTarget = target
+	}
+
 	// YouTube Data API Key (Browser Key)
 	if apiKey := os.Getenv("YOUTUBE_API_KEY"); apiKey != "" {
-		Config.OAuth.YouTube.APIKey = apiKey
-		Config.Stats.YouTubeAPIKey = apiKey  // Stats에도 설정
+		cfg.OAuth.YouTube.APIKey = apiKey
+		cfg.Stats.YouTubeAPIKey = apiKey  // Stats에도 설정
 	}
 
 	// Firebase 설정
 	if projectID := os.Getenv("FIREBASE_PROJECT_ID"); projectID != "" {
-		Config.Firebase.ProjectID = projectID
+		cfg.Firebase.ProjectID = projectID
 	}
 	if credPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); credPath != "" {
-		Config.Firebase.CredentialsPath = credPath
+		cfg.Firebase.CredentialsPath = credPath
+	}
+	if jwksURL := os.Getenv("FIREBASE_JWKS_URL"); jwksURL != "" {
+		cfg.Firebase.JWKSURL = jwksURL
 	}
 
 	// Stats 설정
 	if token := os.Getenv("STATS_UPDATE_TOKEN"); token != "" {
-		Config.Stats.UpdateToken = token
+		cfg.Stats.UpdateToken = token
 	}
 
 	// Security 설정
 	if secret := os.Getenv("JWT_SECRET"); secret != "" {
-		Config.Security.JWTSecret = secret
+		cfg.Security.JWTSecret = secret
+	}
+
+	// 토큰 암호화 키 (형식: "키ID:base64키,키ID:base64키")
+	if keys := os.Getenv("TOKEN_ENCRYPTION_KEYS"); keys != "" {
+		cfg.Security.Encryption.Keys = parseKeyList(keys)
 	}
+	if activeKey := os.Getenv("TOKEN_ENCRYPTION_ACTIVE_KEY"); activeKey != "" {
+		cfg.Security.Encryption.ActiveKeyID = activeKey
+	}
+
+	// 세션 JWT 서명 키 (형식: "kid:키,kid:키")
+	if keys := os.Getenv("SESSION_SIGNING_KEYS"); keys != "" {
+		cfg.Security.Session.Keys = parseKeyList(keys)
+	}
+	if activeKey := os.Getenv("SESSION_ACTIVE_KEY"); activeKey != "" {
+		cfg.Security.Session.ActiveKeyID = activeKey
+	}
+}
+
+// "id:value,id:value" 형식 파싱
+func parseKeyList(s string) map[string]string {
+	keys := map[string]string{}
+	for _, entry := range strings.Split(s, ",") {
+		id, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if ok && id != "" {
+			keys[id] = value
+		}
+	}
+	return keys
 }
 
 // OAuth 설정 초기화
@@ -271,34 +390,39 @@ func InitOAuth2() {
 	log.Printf("  TikTok Redirect URI: %s", Config.OAuth.TikTok.RedirectURI)
 	log.Printf("  YouTube Client ID: %s", maskString(Config.OAuth.YouTube.ClientID))
 	log.Printf("  YouTube API Key: %s", maskString(Config.OAuth.YouTube.APIKey))
+	log.Printf("  Instagram Client ID: %s", maskString(Config.OAuth.Instagram.ClientID))
 }
 
 // GetCronSchedule 크론 스케줄 가져오기
 func GetCronSchedule(name string) (string, bool) {
-	if Config == nil || !Config.Cron.Enabled {
+	cfg := Current()
+	if cfg == nil || !cfg.Cron.Enabled {
 		return "", false
 	}
-	schedule, exists := Config.Cron.Schedules[name]
+	schedule, exists := cfg.Cron.Schedules[name]
 	return schedule, exists
 }
 
 // IsFeatureEnabled 기능 플래그 확인
 func IsFeatureEnabled(feature string) bool {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return false
 	}
 	
 	switch strings.ToLower(feature) {
 	case "tiktok":
-		return Config.Features.TikTokEnabled
+		return cfg.Features.TikTokEnabled
 	case "youtube":
-		return Config.Features.YouTubeEnabled
+		return cfg.Features.YouTubeEnabled
+	case "instagram":
+		return cfg.Features.InstagramEnabled
 	case "stats":
-		return Config.Features.StatsEnabled
+		return cfg.Features.StatsEnabled
 	case "cron":
-		return Config.Features.CronEnabled
+		return cfg.Features.CronEnabled
 	case "analytics":
-		return Config.Features.AnalyticsEnabled
+		return cfg.Features.AnalyticsEnabled
 	default:
 		return false
 	}
@@ -306,26 +430,29 @@ func IsFeatureEnabled(feature string) bool {
 
 // GetLogLevel 로그 레벨 가져오기
 func GetLogLevel() string {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return "info"
 	}
-	return Config.Logging.Level
+	return cfg.Logging.Level
 }
 
 // GetPort 포트 번호 가져오기
 func GetPort() string {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return "8080"
 	}
-	return Config.App.Port
+	return cfg.App.Port
 }
 
 // IsDebugMode 디버그 모드 확인
 func IsDebugMode() bool {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return false
 	}
-	return Config.App.Debug
+	return cfg.App.Debug
 }
 
 // 문자열 마스킹 (보안)
@@ -338,32 +465,36 @@ func maskString(s string) string {
 
 // GetDatabasePath SQLite 데이터베이스 경로
 func GetDatabasePath() string {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return "adfit.db"
 	}
-	return Config.Database.Path
+	return cfg.Database.Path
 }
 
 // GetStatsUpdateToken 통계 업데이트 토큰
 func GetStatsUpdateToken() string {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return "adfit-stats-update-token"
 	}
-	return Config.Stats.UpdateToken
+	return cfg.Stats.UpdateToken
 }
 
 // GetYouTubeAPIKey YouTube API 키
 func GetYouTubeAPIKey() string {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return ""
 	}
-	return Config.Stats.YouTubeAPIKey
+	return cfg.Stats.YouTubeAPIKey
 }
 
 // GetStatsBatchSize 통계 배치 크기
 func GetStatsBatchSize() int {
-	if Config == nil {
+	cfg := Current()
+	if cfg == nil {
 		return 50
 	}
-	return Config.Stats.BatchSize
+	return cfg.Stats.BatchSize
 }
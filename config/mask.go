@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 출력 시 값 대신 표시할 문자열
+const maskedValue = "********"
+
+// Masked 비밀 값(시크릿, 토큰, 키)을 가린 복사본 (원본은 바뀌지 않음)
+func (c *AppConfig) Masked() *AppConfig {
+	masked := *c
+	masked.Environments = nil
+
+	for _, p := range []*OAuthProvider{&masked.OAuth.TikTok, &masked.OAuth.YouTube, &masked.OAuth.Instagram} {
+		p.ClientSecret = maskSecret(p.ClientSecret)
+		p.APIKey = maskSecret(p.APIKey)
+	}
+	masked.Database.Password = maskSecret(masked.Database.Password)
+	masked.Stats.UpdateToken = maskSecret(masked.Stats.UpdateToken)
+	masked.Stats.YouTubeAPIKey = maskSecret(masked.Stats.YouTubeAPIKey)
+	masked.Security.JWTSecret = maskSecret(masked.Security.JWTSecret)
+	masked.Security.Encryption.Keys = maskSecretMap(masked.Security.Encryption.Keys)
+	masked.Security.Session.Keys = maskSecretMap(masked.Security.Session.Keys)
+	return &masked
+}
+
+// MaskedYAML 비밀 값을 가린 최종 설정 YAML (environments 블록 제외)
+func (c *AppConfig) MaskedYAML() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(c.Masked()); err != nil {
+		return nil, err
+	}
+	removeMappingKey(&node, "environments")
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 빈 값은 그대로 (설정 여부는 보이도록)
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return maskedValue
+}
+
+func maskSecretMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(m))
+	for k, v := range m {
+		masked[k] = maskSecret(v)
+	}
+	return masked
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 환경별 오버라이드 병합 규칙 (environments.<환경> 블록을 기본 설정 위에 깊은 병합)
+//
+//   - 맵: 키별로 병합 (오버라이드에 없는 키는 기본값 유지), !replace 태그를 붙이면 통째로 교체
+//   - 목록: 통째로 교체, !append 태그를 붙이면 기본 목록 뒤에 추가
+//   - 스칼라(문자열/숫자/불리언): 오버라이드에 키가 있으면 그 값 (false/0/""도 그대로 적용)
+//   - null (키: ~ 또는 키:): 기본값 제거 → 빈 값(false/0/"")으로 되돌림
+const (
+	appendTag  = "!append"
+	replaceTag = "!replace"
+)
+
+// environmentNode environments.<env> 블록 (없으면 nil)
+func environmentNode(root *yaml.Node, env string) *yaml.Node {
+	environments := mappingValue(root, "environments")
+	if environments == nil || environments.Kind != yaml.MappingNode {
+		return nil
+	}
+	return mappingValue(environments, env)
+}
+
+// mergeNode override를 base 위에 깊은 병합한 결과 (path는 오류 메시지용 YAML 경로)
+func mergeNode(base, override *yaml.Node, path string) (*yaml.Node, error) {
+	switch {
+	case override.Kind == yaml.MappingNode && override.Tag != replaceTag && base != nil && base.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(override.Content); i += 2 {
+			key, value := override.Content[i], override.Content[i+1]
+			idx := mappingIndex(base, key.Value)
+			switch {
+			case isNull(value):
+				if idx >= 0 {
+					base.Content = append(base.Content[:idx], base.Content[idx+2:]...)
+				}
+			case idx < 0:
+				base.Content = append(base.Content, key, stripMergeTags(value))
+			default:
+				merged, err := mergeNode(base.Content[idx+1], value, path+"."+key.Value)
+				if err != nil {
+					return nil, err
+				}
+				base.Content[idx+1] = merged
+			}
+		}
+		return base, nil
+
+	case override.Tag == appendTag:
+		if override.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("%s: %s 태그는 목록에만 사용할 수 있습니다 (%d번째 줄)", path, appendTag, override.Line)
+		}
+		if base != nil && base.Kind == yaml.SequenceNode {
+			base.Content = append(base.Content, stripMergeTags(override).Content...)
+			return base, nil
+		}
+	}
+	return stripMergeTags(override), nil
+}
+
+// 병합용 태그 제거 (그대로 두면 디코딩 시 알 수 없는 태그)
+func stripMergeTags(n *yaml.Node) *yaml.Node {
+	if n.Tag == appendTag || n.Tag == replaceTag {
+		n.Tag = ""
+	}
+	for _, child := range n.Content {
+		stripMergeTags(child)
+	}
+	return n
+}
+
+// 맵에서 key의 값 노드
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if idx := mappingIndex(n, key); idx >= 0 {
+		return n.Content[idx+1]
+	}
+	return nil
+}
+
+// 맵에서 key 노드의 위치 (없으면 -1)
+func mappingIndex(n *yaml.Node, key string) int {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// 맵에서 key 제거
+func removeMappingKey(n *yaml.Node, key string) {
+	if idx := mappingIndex(n, key); idx >= 0 {
+		n.Content = append(n.Content[:idx], n.Content[idx+2:]...)
+	}
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
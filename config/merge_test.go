@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML 문서의 최상위 노드
+func parseNode(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatalf("YAML 파싱 실패: %v", err)
+	}
+	return doc.Content[0]
+}
+
+func TestMergeNode(t *testing.T) {
+	base := `
+app:
+  name: adfit
+  debug: true
+  port: 8080
+cors:
+  origins: [a, b]
+oauth:
+  tiktok:
+    client_id: tk
+    scopes: [user.info.basic]
+features:
+  flags: {x: 1, y: 2}
+`
+	tests := []struct {
+		name     string
+		override string
+		want     string
+	}{
+		{
+			name:     "맵은 키별 병합, 스칼라는 false/0도 적용",
+			override: "app: {debug: false, port: 0}",
+			want:     "{name: adfit, debug: false, port: 0}",
+		},
+		{
+			name:     "목록은 기본적으로 교체",
+			override: "cors: {origins: [c]}",
+			want:     "{origins: [c]}",
+		},
+		{
+			name:     "!append는 기본 목록 뒤에 추가",
+			override: "cors: {origins: !append [c]}",
+			want:     "{origins: [a, b, c]}",
+		},
+		{
+			name:     "!replace는 맵을 통째로 교체",
+			override: "features: {flags: !replace {z: 3}}",
+			want:     "{flags: {z: 3}}",
+		},
+		{
+			name:     "null은 기본값 제거",
+			override: "oauth: {tiktok: {scopes: ~}}",
+			want:     "{tiktok: {client_id: tk}}",
+		},
+		{
+			name:     "기본에 없는 키는 추가 (중첩 태그도 제거)",
+			override: "oauth: {youtube: {scopes: !append [yt]}}",
+			want:     "{tiktok: {client_id: tk, scopes: [user.info.basic]}, youtube: {scopes: [yt]}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := mergeNode(parseNode(t, base), parseNode(t, tt.override), "")
+			if err != nil {
+				t.Fatalf("mergeNode: %v", err)
+			}
+
+			var got map[string]interface{}
+			if err := merged.Decode(&got); err != nil {
+				t.Fatalf("병합 결과 디코딩 실패: %v", err)
+			}
+			var want interface{}
+			if err := yaml.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("want 파싱 실패: %v", err)
+			}
+			section := strings.SplitN(strings.TrimSpace(tt.override), ":", 2)[0]
+			if !reflect.DeepEqual(got[section], want) {
+				t.Errorf("%s = %v, want %v", section, got[section], want)
+			}
+		})
+	}
+}
+
+func TestMergeNodeAppendOnNonList(t *testing.T) {
+	_, err := mergeNode(parseNode(t, "app: {name: adfit}"), parseNode(t, "app: {name: !append other}"), "")
+	if err == nil || !strings.Contains(err.Error(), ".app.name") {
+		t.Errorf("err = %v, 목록이 아닌 값의 !append는 경로와 함께 거부해야 함", err)
+	}
+}
+
+func TestEnvironmentNode(t *testing.T) {
+	root := parseNode(t, "environments:\n  production:\n    app: {debug: false}\n")
+	if environmentNode(root, "production") == nil {
+		t.Error("production 블록을 찾지 못함")
+	}
+	if environmentNode(root, "staging") != nil {
+		t.Error("없는 환경은 nil이어야 함")
+	}
+}
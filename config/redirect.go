@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// RedirectTargetURL 이름으로 등록된 리다이렉트 대상 주소 (name이 비어 있으면 기본 대상)
+func RedirectTargetURL(name, platform string) (string, error) {
+	cfg := Current()
+	if cfg == nil || len(cfg.OAuth.RedirectTargets) == 0 {
+		return "", fmt.Errorf("redirect targets are not configured")
+	}
+	if name == "" {
+		name = cfg.OAuth.DefaultRedirectTarget
+	}
+
+	target, ok := cfg.OAuth.RedirectTargets[name]
+	if !ok {
+		return "", fmt.Errorf("unknown redirect target: %q", name)
+	}
+	raw := target.URL
+	if u, ok := target.Platforms[platform]; ok {
+		raw = u
+	}
+	raw = strings.ReplaceAll(raw, "{platform}", platform)
+
+	if err := ValidateRedirectURL(raw); err != nil {
+		return "", fmt.Errorf("redirect target %q: %v", name, err)
+	}
+	return raw, nil
+}
+
+// HasRedirectTarget 등록된 리다이렉트 대상인지
+func HasRedirectTarget(name string) bool {
+	cfg := Current()
+	if cfg == nil {
+		return false
+	}
+	_, ok := cfg.OAuth.RedirectTargets[name]
+	return ok
+}
+
+// ValidateRedirectURL https, 로컬 http, 앱 커스텀 스킴(adfit://)만 허용
+func ValidateRedirectURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		if u.Host == "" {
+			return fmt.Errorf("missing host: %s", raw)
+		}
+	case "http":
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return fmt.Errorf("http is only allowed for localhost: %s", raw)
+		}
+	case "", "javascript", "data", "file", "vbscript", "blob":
+		return fmt.Errorf("scheme not allowed: %s", raw)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 현재 설정 (재로드 시 통째로 교체)
+var (
+	mu          sync.RWMutex
+	loadedPath  string
+	subscribers []subscriber
+)
+
+type subscriber struct {
+	name string
+	fn   func(cfg *AppConfig) error
+}
+
+// Current 현재 적용 중인 설정 (요청 처리 중에는 Config 대신 사용, 로드 전이면 nil)
+func Current() *AppConfig {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Config
+}
+
+func setCurrent(cfg *AppConfig, path string) {
+	mu.Lock()
+	defer mu.Unlock()
+	Config = cfg
+	loadedPath = path
+	initializeOAuthConfigs()
+}
+
+// OnReload 설정이 바뀌었을 때 호출할 함수 등록 (등록 순서대로 호출)
+func OnReload(name string, fn func(cfg *AppConfig) error) {
+	mu.Lock()
+	defer mu.Unlock()
+	subscribers = append(subscribers, subscriber{name: name, fn: fn})
+}
+
+// Reload 설정 파일을 다시 읽어 검증 후 교체
+//
+// YAML 오류나 새로 생긴 검증 오류가 있으면 거부하고 기존 설정을 유지합니다.
+// (개발 환경에서 원래 있던 경고, 예: 비어 있는 client_id는 거부 사유가 아님)
+func Reload() error {
+	mu.RLock()
+	path, old := loadedPath, Config
+	mu.RUnlock()
+	if path == "" {
+		return fmt.Errorf("설정 파일이 로드되지 않았습니다")
+	}
+
+	cfg, err := Load(path, "")
+	if err != nil {
+		return err
+	}
+	if problems := newProblems(Validate(old), Validate(cfg)); len(problems) > 0 {
+		return problems
+	}
+	if old != nil {
+		for _, section := range keepRestartRequired(old, cfg) {
+			log.Printf("⚠️ %s 변경은 서버 재시작 후 적용됩니다", section)
+		}
+		if reflect.DeepEqual(old, cfg) {
+			return nil
+		}
+	}
+	setCurrent(cfg, path)
+	log.Printf("🔄 설정 재로드 완료: %s (환경: %s)", path, cfg.App.Environment)
+
+	mu.RLock()
+	subs := append([]subscriber(nil), subscribers...)
+	mu.RUnlock()
+	for _, s := range subs {
+		if err := s.fn(cfg); err != nil {
+			log.Printf("❌ 설정 재로드 반영 실패 (%s): %v", s.name, err)
+		}
+	}
+	return nil
+}
+
+// Watch 설정 파일 변경(interval마다 확인)과 SIGHUP에 재로드 (ctx가 끝날 때까지)
+func Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	mu.RLock()
+	path := loadedPath
+	mu.RUnlock()
+	last := fileVersion(path)
+
+	reload := func(reason string) {
+		if err := Reload(); err != nil {
+			log.Printf("❌ 설정 재로드 거부 (%s, 기존 설정 유지): %v", reason, err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			last = fileVersion(path)
+			reload("SIGHUP")
+		case <-ticker.C:
+			if v := fileVersion(path); v != last {
+				last = v
+				reload("파일 변경")
+			}
+		}
+	}
+}
+
+// 파일 변경 감지용 (수정 시각 + 크기)
+func fileVersion(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// 기존 설정에 없던 검증 오류만
+func newProblems(before, after error) ValidationErrors {
+	afterErrs, _ := after.(ValidationErrors)
+	beforeErrs, _ := before.(ValidationErrors)
+	existing := make(map[FieldError]bool, len(beforeErrs))
+	for _, e := range beforeErrs {
+		existing[e] = true
+	}
+	var problems ValidationErrors
+	for _, e := range afterErrs {
+		if !existing[e] {
+			problems = append(problems, e)
+		}
+	}
+	return problems
+}
+
+// 시작할 때만 읽는 설정 중 바뀐 항목
+//
+// 바뀐 항목은 cfg에 기존 값을 다시 넣어, 재시작 전까지 Current()가 실제로 쓰이는 값을 가리키게 합니다.
+func keepRestartRequired(old, cfg *AppConfig) []string {
+	var sections []string
+	for _, s := range []struct {
+		path       string
+		before, to interface{} // 같은 타입의 필드 포인터
+	}{
+		{"app.port", &old.App.Port, &cfg.App.Port},
+		{"database", &old.Database, &cfg.Database},
+		{"oauth.tiktok", &old.OAuth.TikTok, &cfg.OAuth.TikTok},
+		{"oauth.youtube", &old.OAuth.YouTube, &cfg.OAuth.YouTube},
+		{"oauth.instagram", &old.OAuth.Instagram, &cfg.OAuth.Instagram},
+		{"security.encryption", &old.Security.Encryption, &cfg.Security.Encryption},
+		{"security.session", &old.Security.Session, &cfg.Security.Session},
+		{"security.jwt_secret", &old.Security.JWTSecret, &cfg.Security.JWTSecret},
+		{"security.trusted_proxies", &old.Security.TrustedProxies, &cfg.Security.TrustedProxies},
+	} {
+		before, to := reflect.ValueOf(s.before).Elem(), reflect.ValueOf(s.to).Elem()
+		if !reflect.DeepEqual(before.Interface(), to.Interface()) {
+			sections = append(sections, s.path)
+			to.Set(before)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestKeepRestartRequired(t *testing.T) {
+	old := &AppConfig{}
+	old.App.Port = "8080"
+	old.OAuth.TikTok.ClientID = "old-id"
+	old.CORS.AllowedOrigins = []string{"https://a.example.com"}
+
+	cfg := &AppConfig{}
+	cfg.App.Port = "9090"
+	cfg.OAuth.TikTok.ClientID = "new-id"
+	cfg.CORS.AllowedOrigins = []string{"https://b.example.com"}
+
+	sections := keepRestartRequired(old, cfg)
+	if len(sections) != 2 || sections[0] != "app.port" || sections[1] != "oauth.tiktok" {
+		t.Errorf("sections = %v", sections)
+	}
+	// 재시작 필요 항목은 기존 값 유지, 나머지는 새 값
+	if cfg.App.Port != "8080" || cfg.OAuth.TikTok.ClientID != "old-id" || cfg.CORS.AllowedOrigins[0] != "https://b.example.com" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
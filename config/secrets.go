@@ -0,0 +1,47 @@
+package config
+
+import (
+	"adfit-oauth/secrets"
+)
+
+// resolveSecrets 비밀 값 항목의 참조(env:, file:, sm://)를 실제 값으로 변환
+//
+// 실패한 항목은 빈 값으로 두고 Validate에서 YAML 경로와 함께 보고합니다
+// (운영 환경에서는 시작하지 않음, 그 외에는 경고).
+func resolveSecrets(cfg *AppConfig) {
+	cfg.secretErrors = nil
+	resolve := func(path string, value *string) {
+		resolved, err := secrets.Resolve(*value)
+		if err != nil {
+			cfg.secretErrors.add(path, "%v", err)
+			resolved = ""
+		}
+		*value = resolved
+	}
+	resolveMap := func(path string, values map[string]string) {
+		for _, key := range sortedKeys(values) {
+			value := values[key]
+			resolve(path+"."+key, &value)
+			values[key] = value
+		}
+	}
+
+	for _, p := range []struct {
+		name     string
+		provider *OAuthProvider
+	}{
+		{"tiktok", &cfg.OAuth.TikTok},
+		{"youtube", &cfg.OAuth.YouTube},
+		{"instagram", &cfg.OAuth.Instagram},
+	} {
+		resolve("oauth."+p.name+".client_id", &p.provider.ClientID)
+		resolve("oauth."+p.name+".client_secret", &p.provider.ClientSecret)
+		resolve("oauth."+p.name+".api_key", &p.provider.APIKey)
+	}
+	resolve("database.password", &cfg.Database.Password)
+	resolve("stats.update_token", &cfg.Stats.UpdateToken)
+	resolve("stats.youtube_api_key", &cfg.Stats.YouTubeAPIKey)
+	resolve("security.jwt_secret", &cfg.Security.JWTSecret)
+	resolveMap("security.encryption.keys", cfg.Security.Encryption.Keys)
+	resolveMap("security.session.keys", cfg.Security.Session.Keys)
+}
@@ -0,0 +1,410 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 예전 소스에 공개되어 있던 stats.update_token 기본값
+const publicStatsToken = "adfit-stats-update-token"
+
+// FieldError 설정 항목 하나의 문제 (Path는 YAML 경로, 예: oauth.tiktok.client_id)
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors 설정 검증에서 발견된 모든 문제 (첫 번째 오류에서 멈추지 않음)
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	lines := make([]string, 0, len(v)+1)
+	lines = append(lines, fmt.Sprintf("설정 오류 %d개", len(v)))
+	for _, e := range v {
+		lines = append(lines, "  - "+e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (v *ValidationErrors) add(path, format string, args ...interface{}) {
+	*v = append(*v, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// 지원하는 값 목록
+var (
+	supportedEnvironments  = []string{"development", "staging", "production"}
+	supportedDatabases     = []string{"sqlite"}
+	supportedLinkConflicts = []string{"deny", "transfer"}
+	supportedAlgorithms    = []string{"HS256", "HS384", "HS512"}
+	supportedLogLevels     = []string{"debug", "info", "warn", "error"}
+	supportedLogFormats    = []string{"json", "text"}
+	supportedLogOutputs    = []string{"stdout", "file"}
+)
+
+// 크론 스케줄 형식 (cron.New(cron.WithSeconds())와 같은 파서)
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// IsProduction 운영 환경인지 (app.environment, 설정 파일이 없으면 환경변수 ENVIRONMENT)
+func IsProduction() bool {
+	cfg := Current()
+	if cfg == nil {
+		return os.Getenv("ENVIRONMENT") == "production"
+	}
+	return cfg.App.Environment == "production"
+}
+
+// Validate 설정 전체 검증 (문제가 있으면 ValidationErrors, 없으면 nil)
+func Validate(cfg *AppConfig) error {
+	if cfg == nil {
+		return ValidationErrors{{Path: "", Message: "설정이 로드되지 않았습니다"}}
+	}
+
+	var errs ValidationErrors
+	validateApp(cfg, &errs)
+	validateDatabase(cfg, &errs)
+	validateOAuth(cfg, &errs)
+	validateCORS(cfg, &errs)
+	validateCron(cfg, &errs)
+	validateSecurity(cfg, &errs)
+
+	if cfg.Stats.AllowLegacyToken {
+		switch cfg.Stats.UpdateToken {
+		case "":
+			errs.add("stats.update_token", "allow_legacy_token이 true인데 비어 있습니다 (환경변수 STATS_UPDATE_TOKEN)")
+		case publicStatsToken:
+			errs.add("stats.update_token", "공개된 기본값은 사용할 수 없습니다")
+		}
+	}
+	if cfg.Stats.BatchSize < 0 || cfg.Stats.BatchSize > 50 {
+		errs.add("stats.batch_size", "0~50 사이여야 합니다 (YouTube API 최대 50개): %d", cfg.Stats.BatchSize)
+	}
+	if cfg.Firebase.AuthEnabled && cfg.Firebase.ProjectID == "" {
+		errs.add("firebase.project_id", "firebase.auth_enabled가 true이면 필요합니다")
+	}
+	if cfg.Firebase.JWKSURL != "" {
+		validateHTTPURL(&errs, "firebase.jwks_url", cfg.Firebase.JWKSURL)
+	}
+
+	for _, field := range []struct {
+		path, value string
+		allowed     []string
+	}{
+		{"logging.level", cfg.Logging.Level, supportedLogLevels},
+		{"logging.format", cfg.Logging.Format, supportedLogFormats},
+		{"logging.output", cfg.Logging.Output, supportedLogOutputs},
+	} {
+		if field.value != "" {
+			validateOneOf(&errs, field.path, field.value, field.allowed)
+		}
+	}
+	if cfg.Logging.Output == "file" && cfg.Logging.FilePath == "" {
+		errs.add("logging.file_path", "logging.output이 file이면 필요합니다")
+	}
+
+	// 비밀 값 조회 실패는 그 항목의 다른 오류(비어 있음 등) 대신 보고
+	if len(cfg.secretErrors) > 0 {
+		failed := map[string]bool{}
+		for _, e := range cfg.secretErrors {
+			failed[e.Path] = true
+		}
+		filtered := append(ValidationErrors{}, cfg.secretErrors...)
+		for _, e := range errs {
+			if !failed[e.Path] {
+				filtered = append(filtered, e)
+			}
+		}
+		errs = filtered
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateApp(cfg *AppConfig, errs *ValidationErrors) {
+	validateOneOf(errs, "app.environment", cfg.App.Environment, supportedEnvironments)
+	if cfg.App.Port != "" {
+		if port, err := strconv.Atoi(cfg.App.Port); err != nil || port < 1 || port > 65535 {
+			errs.add("app.port", "1~65535 사이의 숫자여야 합니다: %q", cfg.App.Port)
+		}
+	}
+	if cfg.App.Environment == "production" && cfg.App.Debug {
+		errs.add("app.debug", "운영 환경에서는 false여야 합니다")
+	}
+	for _, env := range sortedKeys(cfg.Environments) {
+		if !contains(supportedEnvironments, env) {
+			errs.add("environments."+env, "지원하지 않는 환경입니다 (사용 가능: %s)", strings.Join(supportedEnvironments, ", "))
+		}
+	}
+}
+
+func validateDatabase(cfg *AppConfig, errs *ValidationErrors) {
+	if cfg.Database.Type == "" {
+		errs.add("database.type", "필요합니다 (사용 가능: %s)", strings.Join(supportedDatabases, ", "))
+		return
+	}
+	if !contains(supportedDatabases, cfg.Database.Type) {
+		errs.add("database.type", "지원하지 않는 데이터베이스입니다: %q (사용 가능: %s)", cfg.Database.Type, strings.Join(supportedDatabases, ", "))
+		return
+	}
+	if cfg.Database.Path == "" {
+		errs.add("database.path", "database.type이 sqlite이면 필요합니다")
+	}
+}
+
+func validateOAuth(cfg *AppConfig, errs *ValidationErrors) {
+	// 기능 플래그(features.*_enabled)가 꺼진 플랫폼은 검사하지 않음
+	// (관리자 오버라이드로 켤 때 features.Store.SetOverride에서 ValidateProvider로 확인)
+	providers := []struct {
+		name    string
+		enabled bool
+		config  OAuthProvider
+	}{
+		{"tiktok", cfg.Features.TikTokEnabled, cfg.OAuth.TikTok},
+		{"youtube", cfg.Features.YouTubeEnabled, cfg.OAuth.YouTube},
+		{"instagram", cfg.Features.InstagramEnabled, cfg.OAuth.Instagram},
+	}
+	for _, p := range providers {
+		if p.enabled {
+			validateProvider(errs, p.name, p.config)
+		}
+	}
+
+	if cfg.OAuth.LinkConflict != "" {
+		validateOneOf(errs, "oauth.link_conflict", cfg.OAuth.LinkConflict, supportedLinkConflicts)
+	}
+
+	for _, name := range sortedKeys(cfg.OAuth.RedirectTargets) {
+		target := cfg.OAuth.RedirectTargets[name]
+		path := "oauth.redirect_targets." + name
+		if target.URL == "" {
+			errs.add(path+".url", "필요합니다")
+		} else if err := ValidateRedirectURL(strings.ReplaceAll(target.URL, "{platform}", "tiktok")); err != nil {
+			errs.add(path+".url", "%v", err)
+		}
+		for _, platform := range sortedKeys(target.Platforms) {
+			if err := ValidateRedirectURL(strings.ReplaceAll(target.Platforms[platform], "{platform}", platform)); err != nil {
+				errs.add(path+".platforms."+platform, "%v", err)
+			}
+		}
+	}
+	if name := cfg.OAuth.DefaultRedirectTarget; name != "" {
+		if _, ok := cfg.OAuth.RedirectTargets[name]; !ok {
+			errs.add("oauth.default_redirect_target", "oauth.redirect_targets에 없는 이름입니다: %q", name)
+		}
+	} else if len(cfg.OAuth.RedirectTargets) > 0 {
+		errs.add("oauth.default_redirect_target", "oauth.redirect_targets가 있으면 필요합니다")
+	}
+}
+
+// ValidateProvider OAuth 플랫폼 설정 하나 검증 (기능 플래그 오버라이드로 플랫폼을 켜기 전에 확인)
+func ValidateProvider(cfg *AppConfig, name string) error {
+	var errs ValidationErrors
+	if cfg == nil {
+		errs.add("oauth."+name, "설정을 읽지 못했습니다")
+		return errs
+	}
+	switch name {
+	case "tiktok":
+		validateProvider(&errs, name, cfg.OAuth.TikTok)
+	case "youtube":
+		validateProvider(&errs, name, cfg.OAuth.YouTube)
+	case "instagram":
+		validateProvider(&errs, name, cfg.OAuth.Instagram)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateProvider(errs *ValidationErrors, name string, p OAuthProvider) {
+	path := "oauth." + name
+	if p.ClientID == "" {
+		errs.add(path+".client_id", "비어 있습니다 (환경변수 %s)", providerEnvVar(name, "client_id"))
+	}
+	if p.ClientSecret == "" {
+		errs.add(path+".client_secret", "비어 있습니다 (환경변수 %s)", providerEnvVar(name, "client_secret"))
+	}
+	if p.RedirectURI == "" {
+		errs.add(path+".redirect_uri", "필요합니다")
+	} else {
+		validateHTTPURL(errs, path+".redirect_uri", p.RedirectURI)
+	}
+	for _, field := range []struct{ key, value string }{
+		{"auth_url", p.AuthURL},
+		{"token_url", p.TokenURL},
+		{"api_url", p.APIURL},
+	} {
+		if field.value != "" {
+			validateHTTPURL(errs, path+"."+field.key, field.value)
+		}
+	}
+}
+
+func validateCORS(cfg *AppConfig, errs *ValidationErrors) {
+	for i, origin := range cfg.CORS.AllowedOrigins {
+		path := fmt.Sprintf("cors.allowed_origins[%d]", i)
+		if origin == "*" {
+			if cfg.CORS.AllowCredentials {
+				errs.add(path, "cors.allow_credentials가 true이면 \"*\"를 사용할 수 없습니다")
+			}
+			if cfg.App.Environment == "production" {
+				errs.add(path, "운영 환경에서는 \"*\"를 사용할 수 없습니다")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs.add(path, "scheme://host[:port] 형식이어야 합니다: %q", origin)
+		}
+	}
+}
+
+func validateCron(cfg *AppConfig, errs *ValidationErrors) {
+	for _, name := range sortedKeys(cfg.Cron.Schedules) {
+		spec := cfg.Cron.Schedules[name]
+		if _, err := cronParser.Parse(spec); err != nil {
+			errs.add("cron.schedules."+name, "잘못된 크론 표현식 %q (초 분 시 일 월 요일): %v", spec, err)
+		}
+	}
+}
+
+func validateSecurity(cfg *AppConfig, errs *ValidationErrors) {
+	sec := cfg.Security
+
+	validateDuration(errs, "security.token_ttl", sec.TokenTTL, false)
+	validateDuration(errs, "security.session.access_ttl", sec.Session.AccessTTL, false)
+	validateDuration(errs, "security.session.refresh_ttl", sec.Session.RefreshTTL, false)
+	validateDuration(errs, "security.session.clock_skew", sec.Session.ClockSkew, true)
+
+	for i, alg := range sec.Session.Algorithms {
+		if !contains(supportedAlgorithms, alg) {
+			errs.add(fmt.Sprintf("security.session.algorithms[%d]", i), "지원하지 않는 서명 알고리즘입니다: %q (사용 가능: %s)", alg, strings.Join(supportedAlgorithms, ", "))
+		}
+	}
+	if id := sec.Session.ActiveKeyID; id != "" {
+		if _, ok := sec.Session.Keys[id]; !ok {
+			errs.add("security.session.active_key_id", "security.session.keys에 없는 키입니다: %q", id)
+		}
+	} else if len(sec.Session.Keys) > 0 {
+		errs.add("security.session.active_key_id", "security.session.keys가 있으면 필요합니다")
+	}
+	for _, kid := range sortedKeys(sec.Session.Keys) {
+		if sec.Session.Keys[kid] == "" {
+			errs.add("security.session.keys."+kid, "비어 있습니다")
+		}
+	}
+
+	for _, id := range sortedKeys(sec.Encryption.Keys) {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(sec.Encryption.Keys[id]))
+		if err != nil {
+			errs.add("security.encryption.keys."+id, "base64 디코딩 실패: %v", err)
+		} else if len(key) != 32 {
+			errs.add("security.encryption.keys."+id, "32바이트여야 합니다 (현재 %d바이트)", len(key))
+		}
+	}
+	if id := sec.Encryption.ActiveKeyID; id != "" {
+		if _, ok := sec.Encryption.Keys[id]; !ok {
+			errs.add("security.encryption.active_key_id", "security.encryption.keys에 없는 키입니다: %q", id)
+		}
+	} else if len(sec.Encryption.Keys) > 0 {
+		errs.add("security.encryption.active_key_id", "security.encryption.keys가 있으면 필요합니다")
+	}
+
+	rl := sec.RateLimit
+	for _, field := range []struct {
+		path  string
+		value int
+	}{
+		{"security.rate_limit.requests_per_minute", rl.RequestsPerMinute},
+		{"security.rate_limit.burst", rl.Burst},
+		{"security.rate_limit.oauth.requests_per_minute", rl.OAuth.RequestsPerMinute},
+		{"security.rate_limit.oauth.burst", rl.OAuth.Burst},
+		{"security.rate_limit.stats.requests_per_minute", rl.Stats.RequestsPerMinute},
+		{"security.rate_limit.stats.burst", rl.Stats.Burst},
+	} {
+		if field.value < 0 {
+			errs.add(field.path, "0 이상이어야 합니다: %d", field.value)
+		}
+	}
+
+	for i, proxy := range sec.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs.add(fmt.Sprintf("security.trusted_proxies[%d]", i), "IP 또는 CIDR이어야 합니다: %q", proxy)
+			}
+		}
+	}
+
+	// 운영 환경: 세션 서명 키가 없으면 재시작마다 임시 키로 바뀌어 모든 세션이 만료됨
+	if cfg.App.Environment == "production" && sec.JWTSecret == "" && len(sec.Session.Keys) == 0 {
+		errs.add("security.jwt_secret", "운영 환경에서는 필요합니다 (환경변수 JWT_SECRET 또는 security.session.keys)")
+	}
+}
+
+func validateDuration(errs *ValidationErrors, path, value string, allowZero bool) {
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	switch {
+	case err != nil:
+		errs.add(path, "잘못된 시간 형식입니다 (예: 15m, 24h): %q", value)
+	case d < 0 || (d == 0 && !allowZero):
+		errs.add(path, "0보다 커야 합니다: %q", value)
+	}
+}
+
+func validateHTTPURL(errs *ValidationErrors, path, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add(path, "http(s) 절대 주소여야 합니다: %q", raw)
+	}
+}
+
+func validateOneOf(errs *ValidationErrors, path, value string, allowed []string) {
+	if !contains(allowed, value) {
+		errs.add(path, "지원하지 않는 값입니다: %q (사용 가능: %s)", value, strings.Join(allowed, ", "))
+	}
+}
+
+// 검증 메시지에 표시할 환경변수 이름
+func providerEnvVar(provider, field string) string {
+	if provider == "tiktok" && field == "client_id" {
+		return "TIKTOK_CLIENT_KEY"
+	}
+	return strings.ToUpper(provider + "_" + field)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,19 @@
+package config
+
+import "testing"
+
+func TestValidateProvider(t *testing.T) {
+	cfg := &AppConfig{}
+	cfg.OAuth.TikTok = OAuthProvider{ClientID: "id", ClientSecret: "secret", RedirectURI: "https://example.com/callback"}
+
+	if err := ValidateProvider(cfg, "tiktok"); err != nil {
+		t.Errorf("tiktok: %v", err)
+	}
+	// 기능 플래그가 꺼져 있어도 설정이 없으면 실패
+	if err := ValidateProvider(cfg, "youtube"); err == nil {
+		t.Error("youtube: client_id/secret 없이 통과함")
+	}
+	if err := ValidateProvider(nil, "tiktok"); err == nil {
+		t.Error("설정이 없으면 실패해야 함")
+	}
+}
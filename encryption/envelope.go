@@ -0,0 +1,189 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 암호문 형식: enc:v1:<key id>:<wrapped data key>:<nonce+ciphertext>
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey = errors.New("알 수 없는 암호화 키")
+	ErrMalformed  = errors.New("잘못된 암호문 형식")
+)
+
+// Keyring 마스터 키 목록 (봉투 암호화)
+//
+// 값마다 새 데이터 키(AES-256)를 만들어 AES-GCM으로 암호화하고,
+// 데이터 키는 활성 마스터 키로 다시 암호화(wrap)해서 함께 저장합니다.
+// 이전 마스터 키는 복호화용으로만 남겨 두면 키 교체 후에도 기존 값을 읽을 수 있습니다.
+type Keyring struct {
+	activeID string
+	keys     map[string][]byte
+}
+
+// NewKeyring 마스터 키(32바이트)로 Keyring 생성
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("활성 키 %q가 키 목록에 없습니다", activeID)
+	}
+	for id, key := range keys {
+		if strings.Contains(id, ":") || id == "" {
+			return nil, fmt.Errorf("잘못된 키 ID: %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("키 %q는 32바이트여야 합니다 (현재 %d바이트)", id, len(key))
+		}
+	}
+	return &Keyring{activeID: activeID, keys: keys}, nil
+}
+
+// ParseKeys base64 인코딩된 키 맵 디코딩
+func ParseKeys(encoded map[string]string) (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(encoded))
+	for id, value := range encoded {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("키 %q 디코딩 실패: %v", id, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// ActiveKeyID 새 값 암호화에 사용하는 키 ID
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt 평문 암호화 (빈 문자열은 그대로)
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.activeID], dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.activeID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt 암호문 복호화
+func (k *Keyring) Decrypt(value string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if !IsEncrypted(value) || len(parts) != 3 {
+		return "", ErrMalformed
+	}
+
+	masterKey, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, parts[0])
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(masterKey, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("데이터 키 복호화 실패: %v", err)
+	}
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("복호화 실패: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted 암호문 여부
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID 암호문에 사용된 키 ID (평문이면 "")
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return id
+}
+
+// AES-GCM 암호화 (nonce + ciphertext)
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// AES-GCM 복호화
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 전역 Keyring (GORM 직렬화에서 사용)
+var (
+	mu      sync.RWMutex
+	current *Keyring
+)
+
+// SetDefault 전역 Keyring 설정 (nil이면 암호화 비활성화)
+func SetDefault(k *Keyring) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = k
+}
+
+// Default 전역 Keyring (설정되지 않았으면 nil)
+func Default() *Keyring {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	k, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	encrypted, err := k.Encrypt("access-token")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !IsEncrypted(encrypted) || KeyID(encrypted) != "k1" || strings.Contains(encrypted, "access-token") {
+		t.Errorf("암호문 형식이 잘못됨: %q", encrypted)
+	}
+
+	// 값마다 새 데이터 키/nonce를 쓰므로 같은 평문도 암호문이 다름
+	again, _ := k.Encrypt("access-token")
+	if again == encrypted {
+		t.Error("같은 평문의 암호문이 같음")
+	}
+
+	decrypted, err := k.Decrypt(encrypted)
+	if err != nil || decrypted != "access-token" {
+		t.Errorf("Decrypt = %q, %v", decrypted, err)
+	}
+
+	if empty, _ := k.Encrypt(""); empty != "" {
+		t.Errorf("빈 문자열 암호화 = %q", empty)
+	}
+	if KeyID("plain-token") != "" {
+		t.Error("평문의 KeyID는 빈 문자열이어야 함")
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, err := old.Encrypt("refresh-token")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	// 새 키로 교체, 이전 키는 복호화용으로 유지
+	rotated, err := NewKeyring("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	if decrypted, err := rotated.Decrypt(encrypted); err != nil || decrypted != "refresh-token" {
+		t.Fatalf("이전 키 암호문 Decrypt = %q, %v", decrypted, err)
+	}
+
+	reencrypted, err := rotated.Encrypt("refresh-token")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if KeyID(reencrypted) != "k2" {
+		t.Errorf("새 암호문 KeyID = %q, want k2", KeyID(reencrypted))
+	}
+
+	// 이전 키를 폐기하면 다시 암호화한 값만 읽을 수 있음
+	retired, _ := NewKeyring("k2", map[string][]byte{"k2": testKey(2)})
+	if _, err := retired.Decrypt(encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("폐기된 키 err = %v, want ErrUnknownKey", err)
+	}
+	if decrypted, err := retired.Decrypt(reencrypted); err != nil || decrypted != "refresh-token" {
+		t.Errorf("Decrypt = %q, %v", decrypted, err)
+	}
+}
+
+func TestKeyringDecryptRejects(t *testing.T) {
+	k, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, _ := k.Encrypt("token")
+
+	// 같은 ID의 다른 키
+	wrongKey, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(9)})
+	if _, err := wrongKey.Decrypt(encrypted); err == nil {
+		t.Error("다른 키로 복호화되면 안 됨")
+	}
+
+	// 변조된 암호문
+	parts := strings.Split(encrypted, ":")
+	data, _ := base64.RawStdEncoding.DecodeString(parts[len(parts)-1])
+	data[len(data)-1] ^= 0xff
+	parts[len(parts)-1] = base64.RawStdEncoding.EncodeToString(data)
+	if _, err := k.Decrypt(strings.Join(parts, ":")); err == nil {
+		t.Error("변조된 암호문이 복호화되면 안 됨")
+	}
+
+	for _, value := range []string{"plain-token", "enc:v1:k1:only-two", "enc:v1:k1:!!:!!"} {
+		if _, err := k.Decrypt(value); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Decrypt(%q) err = %v, want ErrMalformed", value, err)
+		}
+	}
+}
+
+func TestNewKeyringValidation(t *testing.T) {
+	tests := map[string]struct {
+		active string
+		keys   map[string][]byte
+	}{
+		"활성 키 없음":  {"k2", map[string][]byte{"k1": testKey(1)}},
+		"짧은 키":     {"k1", map[string][]byte{"k1": []byte("short")}},
+		"콜론 포함 ID": {"a:b", map[string][]byte{"a:b": testKey(1)}},
+	}
+	for name, tt := range tests {
+		if _, err := NewKeyring(tt.active, tt.keys); err == nil {
+			t.Errorf("%s: 에러여야 함", name)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys(map[string]string{"k1": " " + base64.StdEncoding.EncodeToString(testKey(1)) + "\n"})
+	if err != nil || !bytes.Equal(keys["k1"], testKey(1)) {
+		t.Errorf("ParseKeys = %v, %v", keys, err)
+	}
+	if _, err := ParseKeys(map[string]string{"k1": "not base64!"}); err == nil {
+		t.Error("잘못된 base64는 에러여야 함")
+	}
+}
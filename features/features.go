@@ -0,0 +1,313 @@
+package features
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"adfit-oauth/config"
+	"adfit-oauth/models"
+)
+
+// 기능 플래그 이름 (설정의 features.<이름>_enabled)
+const (
+	TikTok    = "tiktok"
+	YouTube   = "youtube"
+	Instagram = "instagram"
+	Stats     = "stats"
+	Cron      = "cron"
+	Analytics = "analytics"
+)
+
+// Names 오버라이드할 수 있는 기능 플래그
+var Names = []string{TikTok, YouTube, Instagram, Stats, Cron, Analytics}
+
+// 다른 인스턴스에서 바꾼 오버라이드를 다시 읽는 간격
+const cacheTTL = 30 * time.Second
+
+var (
+	ErrUnknownFeature     = errors.New("unknown feature")
+	ErrInvalidCohort      = errors.New("invalid cohort")
+	ErrInvalidEnvironment = errors.New("unsupported environment")
+	// ErrProviderNotConfigured oauth.<플랫폼> 설정이 검증을 통과하지 못해 켤 수 없는 플랫폼
+	ErrProviderNotConfigured = errors.New("provider is not configured")
+)
+
+var cohortPattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// Store 환경/코호트별 오버라이드 (SQLite 저장, 메모리 캐시로 요청마다 조회하지 않음)
+type Store struct {
+	DB *gorm.DB
+
+	mu        sync.RWMutex
+	overrides []models.FeatureOverride
+	cohorts   map[string][]string // user_id → 코호트
+	loadedAt  time.Time
+	onChange  []func()
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{DB: db}
+}
+
+// Refresh DB에서 오버라이드/코호트 다시 읽기
+func (s *Store) Refresh() error {
+	var overrides []models.FeatureOverride
+	if err := s.DB.Order("id").Find(&overrides).Error; err != nil {
+		return err
+	}
+	var members []models.FeatureCohortMember
+	if err := s.DB.Find(&members).Error; err != nil {
+		return err
+	}
+	cohorts := map[string][]string{}
+	for _, m := range members {
+		cohorts[m.UserID] = append(cohorts[m.UserID], m.Cohort)
+	}
+
+	s.mu.Lock()
+	s.overrides = overrides
+	s.cohorts = cohorts
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// OnChange 관리자 API로 오버라이드가 바뀌었을 때 호출 (예: 크론 스케줄 다시 적용)
+func (s *Store) OnChange(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Enabled 현재 환경에서 userID(비어 있으면 익명)에게 기능이 켜져 있는지
+//
+// 우선순위: 환경+코호트 > 전체 환경+코호트 > 환경 > 전체 환경 > 설정 파일 (같은 단계에서 충돌하면 꺼짐)
+func (s *Store) Enabled(feature, userID string) bool {
+	s.mu.RLock()
+	stale := time.Since(s.loadedAt) > cacheTTL
+	s.mu.RUnlock()
+	if stale {
+		if err := s.Refresh(); err != nil {
+			log.Printf("⚠️ 기능 플래그 오버라이드 조회 실패 (이전 값 사용): %v", err)
+		}
+	}
+
+	env := ""
+	if cfg := config.Current(); cfg != nil {
+		env = cfg.App.Environment
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	userCohorts := map[string]bool{}
+	if userID != "" {
+		for _, cohort := range s.cohorts[userID] {
+			userCohorts[cohort] = true
+		}
+	}
+
+	const tiers = 4
+	var decided [tiers]*bool
+	for _, o := range s.overrides {
+		if o.Feature != feature || (o.Environment != "" && o.Environment != env) {
+			continue
+		}
+		if o.Cohort != "" && !userCohorts[o.Cohort] {
+			continue
+		}
+		tier := 3
+		switch {
+		case o.Cohort != "" && o.Environment != "":
+			tier = 0
+		case o.Cohort != "":
+			tier = 1
+		case o.Environment != "":
+			tier = 2
+		}
+		enabled := o.Enabled
+		if decided[tier] != nil && !*decided[tier] {
+			enabled = false
+		}
+		decided[tier] = &enabled
+	}
+	for _, d := range decided {
+		if d != nil {
+			return *d
+		}
+	}
+	return Configured(feature)
+}
+
+// Configured 설정 파일(features.<이름>_enabled) 값
+//
+// 설정을 읽지 못했으면 기존처럼 TikTok/YouTube/통계/분석만 켜고 Instagram/크론은 끕니다.
+func Configured(feature string) bool {
+	if config.Current() == nil {
+		return feature != Instagram && feature != Cron
+	}
+	return config.IsFeatureEnabled(feature)
+}
+
+// Overrides 저장된 오버라이드 전체
+func (s *Store) Overrides() ([]models.FeatureOverride, error) {
+	var overrides []models.FeatureOverride
+	err := s.DB.Order("feature, environment, cohort").Find(&overrides).Error
+	return overrides, err
+}
+
+// SetOverride 오버라이드 추가/변경 (같은 기능+환경+코호트는 하나만)
+func (s *Store) SetOverride(feature, env, cohort string, enabled bool, updatedBy string) (*models.FeatureOverride, error) {
+	if err := validate(feature, env, cohort); err != nil {
+		return nil, err
+	}
+	// 설정 검증은 꺼진 플랫폼을 건너뛰므로, client_id/secret 없이 켜서 깨진 OAuth 플로우가 열리지 않게 여기서 확인
+	if enabled && (feature == TikTok || feature == YouTube || feature == Instagram) {
+		if err := config.ValidateProvider(config.Current(), feature); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProviderNotConfigured, err)
+		}
+	}
+	override := &models.FeatureOverride{
+		Feature:     feature,
+		Environment: env,
+		Cohort:      cohort,
+		Enabled:     enabled,
+		UpdatedBy:   updatedBy,
+	}
+	err := s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "feature"}, {Name: "environment"}, {Name: "cohort"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_by", "updated_at"}),
+	}).Create(override).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Where("feature = ? AND environment = ? AND cohort = ?", feature, env, cohort).First(override).Error; err != nil {
+		return nil, err
+	}
+	s.changed()
+	return override, nil
+}
+
+// DeleteOverride 오버라이드 삭제 (설정 파일 값으로 되돌림)
+func (s *Store) DeleteOverride(feature, env, cohort string) (int64, error) {
+	if err := validate(feature, env, cohort); err != nil {
+		return 0, err
+	}
+	result := s.DB.Where("feature = ? AND environment = ? AND cohort = ?", feature, env, cohort).Delete(&models.FeatureOverride{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	s.changed()
+	return result.RowsAffected, nil
+}
+
+// Members 코호트에 속한 사용자
+func (s *Store) Members(cohort string) ([]models.FeatureCohortMember, error) {
+	var members []models.FeatureCohortMember
+	err := s.DB.Where("cohort = ?", cohort).Order("id").Find(&members).Error
+	return members, err
+}
+
+// AddMembers 코호트에 사용자 추가 (이미 있으면 무시)
+func (s *Store) AddMembers(cohort string, userIDs []string, addedBy string) (int64, error) {
+	if !cohortPattern.MatchString(cohort) {
+		return 0, fmt.Errorf("%w: %q (소문자, 숫자, _, - 최대 64자)", ErrInvalidCohort, cohort)
+	}
+	var added int64
+	for _, userID := range userIDs {
+		if userID == "" {
+			continue
+		}
+		result := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.FeatureCohortMember{
+			Cohort:  cohort,
+			UserID:  userID,
+			AddedBy: addedBy,
+		})
+		if result.Error != nil {
+			return added, result.Error
+		}
+		added += result.RowsAffected
+	}
+	s.changed()
+	return added, nil
+}
+
+// RemoveMember 코호트에서 사용자 제거
+func (s *Store) RemoveMember(cohort, userID string) (int64, error) {
+	result := s.DB.Where("cohort = ? AND user_id = ?", cohort, userID).Delete(&models.FeatureCohortMember{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	s.changed()
+	return result.RowsAffected, nil
+}
+
+// 캐시 갱신 + 구독자 호출
+func (s *Store) changed() {
+	if err := s.Refresh(); err != nil {
+		log.Printf("⚠️ 기능 플래그 오버라이드 조회 실패: %v", err)
+	}
+	s.mu.RLock()
+	callbacks := append([]func(){}, s.onChange...)
+	s.mu.RUnlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+func validate(feature, env, cohort string) error {
+	if !IsKnown(feature) {
+		return fmt.Errorf("%w: %q", ErrUnknownFeature, feature)
+	}
+	if env != "" && env != "development" && env != "staging" && env != "production" {
+		return fmt.Errorf("%w: %q", ErrInvalidEnvironment, env)
+	}
+	if cohort != "" && !cohortPattern.MatchString(cohort) {
+		return fmt.Errorf("%w: %q (소문자, 숫자, _, - 최대 64자)", ErrInvalidCohort, cohort)
+	}
+	return nil
+}
+
+// IsKnown 오버라이드할 수 있는 기능인지
+func IsKnown(feature string) bool {
+	for _, name := range Names {
+		if name == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// 전역 Store (미들웨어/크론에서 사용)
+var (
+	defaultMu sync.RWMutex
+	current   *Store
+)
+
+// SetDefault 전역 Store 설정
+func SetDefault(s *Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	current = s
+}
+
+// Default 전역 Store (설정되지 않았으면 nil)
+func Default() *Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return current
+}
+
+// Enabled 전역 Store 기준 (Store가 없으면 설정 파일의 features.*만 사용)
+func Enabled(feature, userID string) bool {
+	if s := Default(); s != nil {
+		return s.Enabled(feature, userID)
+	}
+	return Configured(feature)
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 요청한 연결 계정의 토큰 조회 조건
+//
+// ?account_id= 로 계정을 선택하고, 없으면 처음 연결한 계정(가장 작은 ID)을 사용합니다.
+// (updated_at은 백그라운드 토큰 갱신마다 바뀌므로 기본 계정 선택에 쓰지 않음)
+func userTokenQuery(db *gorm.DB, c *gin.Context, userID, platform string) *gorm.DB {
+	query := db.Where("user_id = ? AND platform = ?", userID, platform)
+	if accountID := c.Query("account_id"); accountID != "" {
+		return query.Where("account_id = ?", accountID)
+	}
+	return query.Order("id")
+}
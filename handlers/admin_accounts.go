@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/audit"
+	"adfit-oauth/models"
+	"adfit-oauth/services"
+)
+
+// AdminAccountsHandler 관리자용 연결 계정 관리
+type AdminAccountsHandler struct {
+	DB          *gorm.DB
+	Revocations *services.RevocationService
+}
+
+func NewAdminAccountsHandler(db *gorm.DB, revocations *services.RevocationService) *AdminAccountsHandler {
+	return &AdminAccountsHandler{DB: db, Revocations: revocations}
+}
+
+// RevokeAllForUser 사용자의 모든 플랫폼 권한 취소 + 토큰 삭제
+// POST /api/admin/users/:user_id/revoke-all?local_only=true
+func (h *AdminAccountsHandler) RevokeAllForUser(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	var tokens []models.UserToken
+	if err := h.DB.Where("user_id = ?", userID).Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(tokens) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "연결된 계정이 없습니다"})
+		return
+	}
+
+	result, err := h.Revocations.Disconnect(context.Background(), tokens, c.Query("local_only") == "true")
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit.SetAffected(c, int64(result.Disconnected))
+	audit.AddParam(c, "revoked", result.Revoked)
+	audit.AddParam(c, "revoke_pending", result.Pending)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"user_id": userID,
+		"result":  result,
+	})
+}
+
+// GetPendingRevocations 재시도 대기 중이거나 중단된 권한 취소 목록
+func (h *AdminAccountsHandler) GetPendingRevocations(c *gin.Context) {
+	var pending []models.PendingRevocation
+	query := h.DB.Omit("token").Order("created_at DESC")
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Find(&pending).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]gin.H, 0, len(pending))
+	for _, p := range pending {
+		items = append(items, gin.H{
+			"id":              p.ID,
+			"user_id":         p.UserID,
+			"platform":        p.Platform,
+			"account_id":      p.AccountID,
+			"attempts":        p.Attempts,
+			"last_error":      p.LastError,
+			"next_attempt_at": p.NextAttemptAt,
+			"gave_up_at":      p.GaveUpAt,
+			"created_at":      p.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
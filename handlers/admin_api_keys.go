@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/apikeys"
+	"adfit-oauth/audit"
+	"adfit-oauth/models"
+)
+
+// AdminAPIKeysHandler 기계 호출용 API 키 발급/조회/폐기
+type AdminAPIKeysHandler struct {
+	Keys *apikeys.Store
+}
+
+func NewAdminAPIKeysHandler(store *apikeys.Store) *AdminAPIKeysHandler {
+	return &AdminAPIKeysHandler{Keys: store}
+}
+
+// ListAPIKeys API 키 목록 (원문/해시는 반환하지 않음)
+func (h *AdminAPIKeysHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.Keys.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]gin.H, 0, len(keys))
+	for i := range keys {
+		items = append(items, apiKeyJSON(&keys[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items, "scopes": apikeys.Scopes})
+}
+
+// CreateAPIKey API 키 발급 (원문 키는 이 응답에서만 확인 가능)
+func (h *AdminAPIKeysHandler) CreateAPIKey(c *gin.Context) {
+	var req struct {
+		Name      string     `json:"name" binding:"required"`
+		Scopes    []string   `json:"scopes" binding:"required"`
+		ExpiresIn string     `json:"expires_in"` // 예: "720h"
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expiresAt := req.ExpiresAt
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires_in"})
+			return
+		}
+		t := time.Now().Add(d)
+		expiresAt = &t
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	audit.AddParam(c, "name", req.Name)
+	audit.AddParam(c, "scopes", req.Scopes)
+
+	plaintext, key, err := h.Keys.Mint(req.Name, req.Scopes, expiresAt, c.GetString("user_id"))
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit.AddParam(c, "prefix", key.Prefix)
+	audit.SetAffected(c, 1)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": apiKeyJSON(key),
+		"key":  plaintext,
+	})
+}
+
+// RevokeAPIKey API 키 폐기 (이후 요청은 401)
+func (h *AdminAPIKeysHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	key, err := h.Keys.Revoke(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit.AddParam(c, "prefix", key.Prefix)
+	audit.SetAffected(c, 1)
+
+	c.JSON(http.StatusOK, gin.H{"data": apiKeyJSON(key)})
+}
+
+func apiKeyJSON(k *models.APIKey) gin.H {
+	return gin.H{
+		"id":           k.ID,
+		"name":         k.Name,
+		"prefix":       k.Prefix,
+		"scopes":       strings.Fields(k.Scopes),
+		"created_by":   k.CreatedBy,
+		"created_at":   k.CreatedAt,
+		"expires_at":   k.ExpiresAt,
+		"last_used_at": k.LastUsedAt,
+		"last_used_ip": k.LastUsedIP,
+		"revoked_at":   k.RevokedAt,
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/audit"
+	"adfit-oauth/models"
+)
+
+// AdminAuditHandler 감사 기록 조회 (컴플라이언스 검토용)
+type AdminAuditHandler struct {
+	Store *audit.Store
+}
+
+func NewAdminAuditHandler(store *audit.Store) *AdminAuditHandler {
+	return &AdminAuditHandler{Store: store}
+}
+
+// GetAuditLogs 감사 기록 조회
+// GET /api/admin/audit?actor=&action=&result=&request_id=&from=&to=&limit=&offset=&format=csv
+//
+// from/to는 RFC3339 또는 YYYY-MM-DD (to가 날짜면 그날 끝까지 포함)
+// CSV는 limit이 없으면 조건에 맞는 기록 전체를 나누어 읽으며 바로 내려보냅니다 (잘리지 않음).
+func (h *AdminAuditHandler) GetAuditLogs(c *gin.Context) {
+	filter := audit.Filter{
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+		Result:    c.Query("result"),
+		RequestID: c.Query("request_id"),
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from (RFC3339 or YYYY-MM-DD)"})
+		return
+	}
+	if filter.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to (RFC3339 or YYYY-MM-DD)"})
+		return
+	}
+
+	csvExport := c.Query("format") == "csv"
+	filter.Limit = 100
+	if csvExport {
+		filter.Limit = 0 // 전체
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+	}
+
+	if !csvExport {
+		logs, total, err := h.Store.Query(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data":   logs,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		})
+		return
+	}
+
+	total, err := h.Store.Count(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "request_id", "actor", "source", "role", "action", "endpoint", "params", "affected", "result", "status", "error", "client_ip"})
+	err = h.Store.Export(filter, func(logs []models.AuditLog) error {
+		for _, l := range logs {
+			w.Write(auditCSVRow(l))
+		}
+		w.Flush()
+		return w.Error()
+	})
+	if err != nil {
+		// 헤더를 이미 보냈으므로 상태 코드는 바꿀 수 없음 (잘린 파일임을 로그로 남김)
+		log.Printf("❌ 감사 기록 CSV 내보내기 중단: %v", err)
+	}
+}
+
+// CSV 한 줄
+func auditCSVRow(l models.AuditLog) []string {
+	return []string{
+		strconv.FormatUint(uint64(l.ID), 10),
+		l.CreatedAt.UTC().Format(time.RFC3339),
+		csvSafe(l.RequestID),
+		csvSafe(l.Actor),
+		l.Source,
+		l.Role,
+		l.Action,
+		csvSafe(l.Endpoint),
+		csvSafe(l.Params),
+		strconv.FormatInt(l.Affected, 10),
+		l.Result,
+		strconv.Itoa(l.Status),
+		csvSafe(l.Error),
+		l.ClientIP,
+	}
+}
+
+// 스프레드시트에서 수식으로 해석되지 않도록
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func parseAuditTime(v string, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/audit"
+	"adfit-oauth/models"
+	"adfit-oauth/services"
+	"adfit-oauth/session"
+)
+
+// AdminAuthHandler 관리자 로그인 + 관리자 계정 관리
+type AdminAuthHandler struct {
+	Accounts *services.AdminAccountService
+	Sessions *session.Manager
+}
+
+func NewAdminAuthHandler(db *gorm.DB, manager *session.Manager) *AdminAuthHandler {
+	return &AdminAuthHandler{
+		Accounts: services.NewAdminAccountService(db),
+		Sessions: manager,
+	}
+}
+
+// Login 관리자 계정 로그인 → 관리자 세션 발급 (scope: admin)
+func (h *AdminAuthHandler) Login(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.Accounts.Authenticate(req.Username, req.Password)
+	if errors.Is(err, services.ErrAdminInvalidCredentials) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "아이디 또는 비밀번호가 올바르지 않습니다"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.Sessions.Issue(session.Subject{
+		UserID: services.AdminSessionUserID(account.Username),
+		Scopes: []string{"admin"},
+		Role:   account.Role,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"access_token":  tokens.AccessToken,
+		"expires_in":    int(tokens.AccessExpiresIn.Seconds()),
+		"refresh_token": tokens.RefreshToken,
+		"role":          account.Role,
+	})
+}
+
+// ListAdmins 관리자 계정 목록
+func (h *AdminAuthHandler) ListAdmins(c *gin.Context) {
+	accounts, err := h.Accounts.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]gin.H, 0, len(accounts))
+	for i := range accounts {
+		items = append(items, adminAccountJSON(&accounts[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// CreateAdmin 관리자 계정 생성
+func (h *AdminAuthHandler) CreateAdmin(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		Role     string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit.AddParam(c, "username", req.Username)
+	audit.AddParam(c, "role", req.Role)
+
+	account, err := h.Accounts.Create(req.Username, req.Password, req.Role)
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": adminAccountJSON(account)})
+}
+
+// UpdateAdmin 권한 변경/비활성화/비밀번호 재설정 (기존 세션의 refresh token은 폐기)
+func (h *AdminAuthHandler) UpdateAdmin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	var req struct {
+		Role     *string `json:"role"`
+		Disabled *bool   `json:"disabled"`
+		Password *string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Role != nil {
+		audit.AddParam(c, "role", *req.Role)
+	}
+	if req.Disabled != nil {
+		audit.AddParam(c, "disabled", *req.Disabled)
+	}
+	audit.AddParam(c, "password_reset", req.Password != nil)
+
+	account, err := h.Accounts.Update(uint(id), services.AdminAccountUpdate{
+		Role:     req.Role,
+		Disabled: req.Disabled,
+		Password: req.Password,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin account not found"})
+		return
+	}
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	audit.AddParam(c, "username", account.Username)
+	audit.SetAffected(c, 1)
+
+	// 변경된 권한은 다시 로그인해야 적용 (access JWT는 만료될 때까지 유효)
+	h.Sessions.RevokeUser(services.AdminSessionUserID(account.Username))
+
+	c.JSON(http.StatusOK, gin.H{"data": adminAccountJSON(account)})
+}
+
+func adminAccountJSON(a *models.AdminAccount) gin.H {
+	return gin.H{
+		"id":            a.ID,
+		"username":      a.Username,
+		"role":          a.Role,
+		"disabled":      a.Disabled,
+		"last_login_at": a.LastLoginAt,
+		"created_at":    a.CreatedAt,
+	}
+}
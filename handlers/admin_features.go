@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/audit"
+	"adfit-oauth/config"
+	"adfit-oauth/features"
+)
+
+// AdminFeaturesHandler 기능 플래그 런타임 오버라이드 (환경/코호트별)
+type AdminFeaturesHandler struct {
+	Features *features.Store
+}
+
+func NewAdminFeaturesHandler(store *features.Store) *AdminFeaturesHandler {
+	return &AdminFeaturesHandler{Features: store}
+}
+
+// ListFeatures 기능별 설정 값 / 적용 값 + 오버라이드 목록
+// GET /api/admin/features?user_id= (user_id가 있으면 그 사용자 기준 적용 값)
+func (h *AdminFeaturesHandler) ListFeatures(c *gin.Context) {
+	overrides, err := h.Features.Overrides()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.Query("user_id")
+	items := make([]gin.H, 0, len(features.Names))
+	for _, name := range features.Names {
+		items = append(items, gin.H{
+			"feature":    name,
+			"configured": features.Configured(name),
+			"enabled":    h.Features.Enabled(name, userID),
+		})
+	}
+
+	env := ""
+	if cfg := config.Current(); cfg != nil {
+		env = cfg.App.Environment
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"environment": env,
+		"user_id":     userID,
+		"data":        items,
+		"overrides":   overrides,
+	})
+}
+
+// SetFeatureOverride 오버라이드 추가/변경
+// PUT /api/admin/features/:feature {"enabled": false, "environment": "production", "cohort": "beta"}
+func (h *AdminFeaturesHandler) SetFeatureOverride(c *gin.Context) {
+	var req struct {
+		Enabled     *bool  `json:"enabled" binding:"required"`
+		Environment string `json:"environment"` // 비어 있으면 모든 환경
+		Cohort      string `json:"cohort"`      // 비어 있으면 모든 사용자
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feature := c.Param("feature")
+	audit.AddParam(c, "enabled", *req.Enabled)
+	audit.AddParam(c, "environment", req.Environment)
+	audit.AddParam(c, "cohort", req.Cohort)
+
+	override, err := h.Features.SetOverride(feature, req.Environment, req.Cohort, *req.Enabled, c.GetString("user_id"))
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(featureErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	audit.SetAffected(c, 1)
+
+	c.JSON(http.StatusOK, gin.H{"data": override})
+}
+
+// DeleteFeatureOverride 오버라이드 삭제 (설정 파일 값으로 되돌림)
+// DELETE /api/admin/features/:feature?environment=&cohort=
+func (h *AdminFeaturesHandler) DeleteFeatureOverride(c *gin.Context) {
+	deleted, err := h.Features.DeleteOverride(c.Param("feature"), c.Query("environment"), c.Query("cohort"))
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(featureErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feature override not found"})
+		return
+	}
+	audit.SetAffected(c, deleted)
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// ListCohortMembers 코호트 사용자 목록
+// GET /api/admin/feature-cohorts/:cohort
+func (h *AdminFeaturesHandler) ListCohortMembers(c *gin.Context) {
+	members, err := h.Features.Members(c.Param("cohort"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// AddCohortMembers 코호트에 사용자 추가
+// POST /api/admin/feature-cohorts/:cohort/members {"user_ids": ["uid1", "uid2"]}
+func (h *AdminFeaturesHandler) AddCohortMembers(c *gin.Context) {
+	var req struct {
+		UserIDs []string `json:"user_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	audit.AddParam(c, "user_ids", req.UserIDs)
+
+	added, err := h.Features.AddMembers(c.Param("cohort"), req.UserIDs, c.GetString("user_id"))
+	if err != nil {
+		audit.SetError(c, err)
+		c.JSON(featureErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	audit.SetAffected(c, added)
+
+	c.JSON(http.StatusOK, gin.H{"added": added})
+}
+
+// RemoveCohortMember 코호트에서 사용자 제거
+// DELETE /api/admin/feature-cohorts/:cohort/members/:user_id
+func (h *AdminFeaturesHandler) RemoveCohortMember(c *gin.Context) {
+	removed, err := h.Features.RemoveMember(c.Param("cohort"), c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if removed == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cohort member not found"})
+		return
+	}
+	audit.SetAffected(c, removed)
+
+	c.JSON(http.StatusOK, gin.H{"removed": removed})
+}
+
+func featureErrorStatus(err error) int {
+	if errors.Is(err, features.ErrUnknownFeature) || errors.Is(err, features.ErrInvalidCohort) || errors.Is(err, features.ErrInvalidEnvironment) ||
+		errors.Is(err, features.ErrProviderNotConfigured) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	
+	"adfit-oauth/audit"
 	"adfit-oauth/services"
 )
 
@@ -25,26 +26,6 @@ func NewAdminStatsHandler() (*AdminStatsHandler, error) {
 	}, nil
 }
 
-// 관리자 인증 미들웨어
-func (h *AdminStatsHandler) AdminAuthRequired() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 간단한 토큰 인증 (실제로는 더 강화된 인증 필요)
-		token := c.GetHeader("Authorization")
-		adminToken := c.GetHeader("X-Admin-Token")
-		
-		if token != "Bearer adfit-stats-update-token" || adminToken != "adfit-admin-secret" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "관리자 권한이 필요합니다",
-				"code":  "ADMIN_AUTH_REQUIRED",
-			})
-			c.Abort()
-			return
-		}
-		
-		c.Next()
-	}
-}
-
 // 저장소 통계 조회
 func (h *AdminStatsHandler) GetStorageStats(c *gin.Context) {
 	stats, err := h.statsService.GetStorageStats()
@@ -76,7 +57,9 @@ func (h *AdminStatsHandler) CleanupOldSnapshots(c *gin.Context) {
 
 	cutoffDate := time.Now().AddDate(0, 0, -days)
 	deletedCount, err := h.statsService.CleanupOldSnapshots(cutoffDate)
+	audit.SetAffected(c, int64(deletedCount))
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "오래된 스냅샷 정리 실패",
 			"details": err.Error(),
@@ -136,7 +119,14 @@ func (h *AdminStatsHandler) DeleteDataByDateRange(c *gin.Context) {
 	}
 
 	result, err := h.statsService.DeleteDataByDateRange(startDate, endDate)
+	var affected int64
+	for collection, n := range result {
+		affected += int64(n)
+		audit.AddParam(c, "deleted_"+collection, n)
+	}
+	audit.SetAffected(c, affected)
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "기간별 데이터 삭제 실패",
 			"details": err.Error(),
@@ -173,7 +163,9 @@ func (h *AdminStatsHandler) DeleteCompetitionHistory(c *gin.Context) {
 	}
 
 	deletedCount, err := h.statsService.DeleteCompetitionHistoryData(competitionID)
+	audit.SetAffected(c, int64(deletedCount))
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "대회 히스토리 데이터 삭제 실패",
 			"details": err.Error(),
@@ -192,6 +184,7 @@ func (h *AdminStatsHandler) DeleteCompetitionHistory(c *gin.Context) {
 func (h *AdminStatsHandler) TriggerDailyAggregation(c *gin.Context) {
 	err := h.statsService.SaveDailyAggregation()
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "일별 집계 실행 실패",
 			"details": err.Error(),
@@ -214,6 +207,7 @@ func (h *AdminStatsHandler) TriggerHourlySnapshots(c *gin.Context) {
 		// 특정 대회만
 		err := h.statsService.SaveCompetitionHourlySnapshot(competitionID)
 		if err != nil {
+			audit.SetError(c, err)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "시간별 스냅샷 저장 실패",
 				"details": err.Error(),
@@ -229,6 +223,7 @@ func (h *AdminStatsHandler) TriggerHourlySnapshots(c *gin.Context) {
 		// 모든 활성 대회
 		err := h.statsService.UpdateAllActiveCompetitions()
 		if err != nil {
+			audit.SetError(c, err)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "전체 시간별 스냅샷 저장 실패",
 				"details": err.Error(),
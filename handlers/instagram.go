@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// InstagramHandler Instagram 전용 API (OAuth 플로우는 OAuthHandler에서 처리)
+type InstagramHandler struct {
+	DB       *gorm.DB
+	Provider *providers.Instagram
+}
+
+// 1. 사용자 정보 조회
+func (h *InstagramHandler) GetUserInfo(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var userToken models.UserToken
+	if err := userTokenQuery(h.DB, c, userID, "instagram").First(&userToken).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instagram not connected"})
+		return
+	}
+
+	profile, err := h.Provider.Profile(context.Background(), userToken.AccessToken)
+	if err != nil {
+		respondInstagramError(c, "Failed to fetch user info", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": profile.Data})
+}
+
+// 2. 미디어(릴스 포함) 목록 조회
+func (h *InstagramHandler) GetVideos(c *gin.Context) {
+	userID := c.GetString("user_id")
+	cursor := c.Query("cursor")
+	maxCount, err := strconv.Atoi(c.DefaultQuery("max_count", "20"))
+	if err != nil || maxCount < 1 || maxCount > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_count must be between 1 and 100"})
+		return
+	}
+
+	var userToken models.UserToken
+	if err := userTokenQuery(h.DB, c, userID, "instagram").First(&userToken).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instagram not connected"})
+		return
+	}
+
+	page, err := h.Provider.Media(context.Background(), userToken.AccessToken, cursor, maxCount)
+	if err != nil {
+		respondInstagramError(c, "Failed to fetch media", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": page})
+}
+
+// Graph API 에러는 400, 그 외는 500
+func respondInstagramError(c *gin.Context, message string, err error) {
+	fmt.Printf("❌ %s: %v\n", message, err)
+
+	var pErr *providers.Error
+	if errors.As(err, &pErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": pErr.Code, "message": pErr.Description}})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
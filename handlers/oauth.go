@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"adfit-oauth/audit"
+	"adfit-oauth/config"
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+	"adfit-oauth/services"
+	"adfit-oauth/session"
+)
+
+// OAuthHandler 플랫폼 공통 OAuth 플로우 (/api/:provider/...)
+type OAuthHandler struct {
+	DB          *gorm.DB
+	States      *services.OAuthStateStore
+	Providers   *providers.Registry
+	Revocations *services.RevocationService
+}
+
+func NewOAuthHandler(db *gorm.DB, registry *providers.Registry) *OAuthHandler {
+	return &OAuthHandler{
+		DB:          db,
+		States:      services.NewOAuthStateStore(db),
+		Providers:   registry,
+		Revocations: services.NewRevocationService(db, registry),
+	}
+}
+
+// URL의 :provider로 프로바이더 조회
+func (h *OAuthHandler) provider(c *gin.Context) (providers.OAuthProvider, bool) {
+	name := c.Param("provider")
+	p, ok := h.Providers.Get(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider: " + name})
+		return nil, false
+	}
+	return p, true
+}
+
+// 1. 로그인 URL 생성 (직접 리다이렉트, ?format=json이면 {"auth_url": ...} 응답)
+//
+// 로그인한 사용자만 시작할 수 있고 state는 그 사용자에게 바인딩됩니다 (다른 사용자의 세션으로 교환 불가).
+// 브라우저 이동에는 Authorization 헤더를 붙일 수 없으므로 웹/앱은 format=json으로 URL을 받아 엽니다.
+// ?feature=videos 또는 ?scopes=video.list로 기본 scope 외의 권한을 추가로 요청할 수 있습니다 (scope 업그레이드).
+// ?redirect_target=mobile처럼 설정에 등록된 이름으로 콜백 후 돌아갈 앱을 고릅니다.
+// 팝업으로 연결할 때는 ?response_mode=web_message&origin=https://adfit.ai 로 opener 창에 결과를 전달합니다.
+func (h *OAuthHandler) GetAuthURL(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+
+	redirectTarget := c.Query("redirect_target")
+	if _, err := appCallbackURL(p.Name(), redirectTarget); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_redirect_target", "details": err.Error()})
+		return
+	}
+
+	responseMode, webOrigin, err := webMessageOptions(c.Query("response_mode"), c.Query("origin"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_response_mode", "details": err.Error()})
+		return
+	}
+
+	scopes, err := requestedScopes(p, c.Query("feature"), c.Query("scopes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope", "details": err.Error()})
+		return
+	}
+
+	// 인증된 사용자 (user_id를 보내면 같아야 함)
+	userID := c.GetString("user_id")
+	if q := c.Query("user_id"); q != "" && q != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "user_id does not match the authenticated user"})
+		return
+	}
+
+	// 서버에서 1회용 state 발급 (인증된 사용자에게 바인딩)
+	oauthState, err := h.States.Issue(p.Name(), services.StateOptions{
+		UserID:         userID,
+		ClientID:       c.Query("client_id"),
+		ClientState:    c.Query("state"),
+		Scopes:         scopes,
+		RedirectTarget: redirectTarget,
+		ResponseMode:   responseMode,
+		WebOrigin:      webOrigin,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue state: " + err.Error()})
+		return
+	}
+
+	// PKCE S256
+	var authURL string
+	if upgrader, ok := p.(providers.ScopeUpgrader); ok && len(scopes) > 0 {
+		authURL = upgrader.AuthURLWithScopes(oauthState.State, services.CodeChallenge(oauthState), scopes)
+	} else {
+		authURL = p.AuthURL(oauthState.State, services.CodeChallenge(oauthState))
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{"auth_url": authURL, "expires_at": oauthState.ExpiresAt})
+		return
+	}
+
+	fmt.Printf("🌐 Redirecting to %s Auth URL: %s\n", p.Name(), authURL)
+	c.Redirect(http.StatusTemporaryRedirect, authURL)
+}
+
+// 2. OAuth 콜백 처리 (Flutter 앱으로 리다이렉트)
+func (h *OAuthHandler) HandleCallback(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	errorParam := c.Query("error")
+
+	fmt.Printf("🔔 %s Callback received - State: %s, Error: %s\n", p.Name(), state, errorParam)
+
+	// state 검증 (알 수 없거나, 재사용되었거나, 만료된 state 거부)
+	oauthState, err := h.States.Consume(p.Name(), state)
+	if err != nil {
+		fmt.Printf("❌ Invalid state: %v\n", err)
+		// 어느 앱에서 시작했는지 알 수 없으므로 기본 대상으로 돌려보냄
+		redirectURL, _ := appCallbackURL(p.Name(), "")
+		redirectURL = fmt.Sprintf("%s?error=invalid_state&error_description=%s", redirectURL, url.QueryEscape(err.Error()))
+		c.Redirect(http.StatusTemporaryRedirect, redirectURL)
+		return
+	}
+
+	// 팝업: 리다이렉트 대신 opener 창에 code/state 전달
+	if oauthState.ResponseMode == responseModeWebMessage {
+		renderWebMessage(c, oauthState.WebOrigin, webMessage{
+			Provider:    p.Name(),
+			Code:        code,
+			State:       state,
+			ClientState: oauthState.ClientState,
+			Error:       errorParam,
+		})
+		return
+	}
+
+	// state에 저장된 대상 (발급 후 설정에서 빠졌으면 기본 대상)
+	redirectURL, err := appCallbackURL(p.Name(), oauthState.RedirectTarget)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		redirectURL, _ = appCallbackURL(p.Name(), "")
+	}
+
+	if errorParam != "" {
+		redirectURL = fmt.Sprintf("%s?error=%s&state=%s", redirectURL, url.QueryEscape(errorParam), url.QueryEscape(state))
+	} else {
+		redirectURL = fmt.Sprintf("%s?code=%s&state=%s", redirectURL, url.QueryEscape(code), url.QueryEscape(state))
+	}
+	if oauthState.ClientState != "" {
+		redirectURL += "&client_state=" + url.QueryEscape(oauthState.ClientState)
+	}
+
+	fmt.Printf("🔁 Redirecting to: %s\n", redirectURL)
+	c.Redirect(http.StatusTemporaryRedirect, redirectURL)
+}
+
+// 3. 토큰 교환
+func (h *OAuthHandler) ExchangeToken(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code   string `json:"code" binding:"required"`
+		State  string `json:"state" binding:"required"`
+		UserID string `json:"user_id"` // (선택) 보내면 인증된 사용자와 같아야 함
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 연결할 사용자는 검증된 자격 증명(세션 JWT 또는 Firebase ID token)에서 가져옴
+	userID := c.GetString("user_id")
+	if req.UserID != "" && req.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "user_id does not match the authenticated user"})
+		return
+	}
+
+	// state로 PKCE code_verifier 조회 (1회용, 인증된 사용자에게 발급된 state만 — 사용자 없이 발급된 이전 state 포함 거부)
+	oauthState, err := h.States.ClaimForExchange(p.Name(), req.State, userID)
+	if errors.Is(err, services.ErrStateNotOwned) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "State was not issued to the authenticated user"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_state", "details": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	token, err := p.Exchange(ctx, req.Code, oauthState.CodeVerifier)
+	if err != nil {
+		fmt.Printf("❌ %s token exchange error: %v\n", p.Name(), err)
+		var pErr *providers.Error
+		if errors.As(err, &pErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange token: " + err.Error()})
+		return
+	}
+
+	// 프로필 조회 (토큰 응답에 계정 ID가 없으면 프로필에서, 일시적인 실패에 대비해 한 번 더 시도)
+	accountID := token.AccountID
+	var profile *providers.Profile
+	for attempt := 1; attempt <= profileAttempts; attempt++ {
+		profile, err = p.Profile(ctx, token.AccessToken)
+		if err == nil || accountID != "" {
+			break
+		}
+		fmt.Printf("⚠️ Failed to get %s profile (attempt %d): %v\n", p.Name(), attempt, err)
+	}
+	if accountID == "" && profile != nil {
+		accountID = profile.AccountID
+	}
+
+	// 계정 ID 없이 저장하면 연결 충돌 확인과 account_id 선택이 불가능하므로 거부
+	if accountID == "" {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "account_unresolved",
+			"message": fmt.Sprintf("Could not resolve the %s account for this token, please try again", p.Name()),
+		})
+		return
+	}
+
+	fmt.Printf("✅ %s token received for user: %s (account: %s)\n", p.Name(), userID, accountID)
+
+	userToken := models.UserToken{
+		UserID:       userID,
+		Platform:     p.Name(),
+		AccountID:    accountID,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		ExpiresAt:    token.ExpiresAt,
+		Scope:        token.Scope,
+		Status:       models.TokenStatusActive,
+		UpdatedAt:    time.Now(),
+	}
+	switch p.Name() {
+	case "tiktok":
+		userToken.OpenID = accountID
+	case "youtube":
+		userToken.ChannelID = accountID
+	case "instagram":
+		userToken.IGUserID = accountID
+	}
+
+	// UPSERT (같은 플랫폼 계정이 있으면 갱신 + scope 병합, 없으면 추가 연결, 중복은 고유 인덱스가 막음)
+	var transferred []models.UserToken
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		// 다른 사용자에게 이미 연결된 계정 (동시 연결은 idx_platform_account_live가 막음)
+		var linked []models.UserToken
+		if err := tx.Where("platform = ? AND account_id = ? AND user_id <> ?", p.Name(), accountID, userID).Find(&linked).Error; err != nil {
+			return err
+		}
+		if len(linked) > 0 {
+			if linkConflictPolicy() != "transfer" {
+				return services.ErrAccountLinked
+			}
+			// 기존 사용자의 연결 해제 (연결 해제 API와 같은 경로, 같은 트랜잭션)
+			// 플랫폼 권한은 취소하지 않음: 같은 플랫폼 계정에 대한 권한이라 방금 받은 토큰까지 무효가 됨
+			if _, err := h.Revocations.WithDB(tx).Disconnect(ctx, linked, true); err != nil {
+				return err
+			}
+			transferred = linked
+		}
+
+		// 연결 해제된(soft delete) 이전 기록 정리
+		if err := tx.Unscoped().Where("user_id = ? AND platform = ? AND account_id = ? AND deleted_at IS NOT NULL", userID, p.Name(), accountID).Delete(&models.UserToken{}).Error; err != nil {
+			return err
+		}
+
+		// scope 업그레이드: 이전에 동의한 scope에 새로 부여된 scope 추가
+		var existing models.UserToken
+		err := tx.Where("user_id = ? AND platform = ? AND account_id = ?", userID, p.Name(), accountID).First(&existing).Error
+		switch {
+		case err == nil:
+			userToken.Scope = providers.MergeScopes(existing.Scope, token.Scope)
+			if userToken.RefreshToken == "" {
+				userToken.RefreshToken = existing.RefreshToken
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		return services.UpsertUserToken(tx, &userToken)
+	})
+	if errors.Is(err, services.ErrAccountLinked) {
+		// 새로 받은 토큰은 저장하지 않음 (권한 취소 시 기존 사용자의 연결까지 끊기므로 취소하지 않음)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "account_already_linked",
+			"details":    fmt.Sprintf("This %s account is already linked to another user", p.Name()),
+			"account_id": accountID,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save token: " + err.Error()})
+		return
+	}
+
+	if len(transferred) > 0 {
+		fmt.Printf("🔀 %s account %s transferred to user: %s\n", p.Name(), accountID, userID)
+		recordTransfer(c, userID, accountID, transferred)
+	}
+
+	tokens, err := session.Default().Issue(session.Subject{UserID: userID, Platform: p.Name(), AccountID: accountID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate JWT"})
+		return
+	}
+
+	response := gin.H{
+		"success":            true,
+		"jwt":                tokens.AccessToken,
+		"session_token":      tokens.AccessToken, // Flutter 앱에서 session_token으로 받음
+		"access_token":       tokens.AccessToken, // 호환성을 위해 둘 다 제공
+		"expires_in":         int(tokens.AccessExpiresIn.Seconds()),
+		"refresh_token":      tokens.RefreshToken, // POST /api/session/refresh 로 access JWT 재발급
+		"refresh_expires_in": int(tokens.RefreshExpiresIn.Seconds()),
+		"account_id":         accountID,
+		"scopes":             providers.ParseScopes(userToken.Scope),
+		"profile":            profile,
+		"transferred":        len(transferred) > 0,
+	}
+
+	// 기존 클라이언트 호환 필드
+	switch p.Name() {
+	case "tiktok":
+		response["open_id"] = accountID
+	case "youtube":
+		if profile != nil {
+			response["channel_info"] = profile.Data
+		} else {
+			response["channel_info"] = nil
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// 4. 토큰 갱신
+func (h *OAuthHandler) RefreshToken(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var userToken models.UserToken
+	if err := userTokenQuery(h.DB, c, userID, p.Name()).First(&userToken).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+		return
+	}
+
+	// 백그라운드 갱신과 겹치지 않도록 토큰별 잠금 안에서 갱신 + 저장
+	if err := services.RefreshTokenLocked(context.Background(), h.DB, p, &userToken); err != nil {
+		fmt.Printf("❌ %s token refresh error: %v\n", p.Name(), err)
+		switch {
+		case providers.IsInvalidGrant(err):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "needs_reconnect", "details": err.Error()})
+		case errors.Is(err, services.ErrTokenSave):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save token"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to refresh token"})
+		}
+		return
+	}
+
+	// 새 JWT 생성
+	tokens, err := session.Default().Issue(session.Subject{UserID: userID, Platform: p.Name(), AccountID: userToken.PlatformAccountID()})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create JWT"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"access_token":  tokens.AccessToken,
+		"expires_in":    int(tokens.AccessExpiresIn.Seconds()),
+		"refresh_token": tokens.RefreshToken,
+	})
+}
+
+// 5. 로그아웃 (플랫폼 권한 취소 + 토큰 삭제)
+//
+// ?account_id= 로 계정 하나만 해제, ?local_only=true 면 플랫폼 권한은 그대로 두고 로컬 토큰만 삭제
+func (h *OAuthHandler) Logout(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+	localOnly := c.Query("local_only") == "true"
+
+	// account_id가 없으면 해당 플랫폼의 모든 계정 연결 해제
+	query := h.DB.Where("user_id = ? AND platform = ?", userID, p.Name())
+	if accountID := c.Query("account_id"); accountID != "" {
+		query = query.Where("account_id = ?", accountID)
+	}
+
+	var tokens []models.UserToken
+	if err := query.Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+
+	result, err := h.Revocations.Disconnect(context.Background(), tokens, localOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Successfully logged out from %s", p.Name()),
+		"result":  result,
+	})
+}
+
+// 6. 연결된 계정 목록
+func (h *OAuthHandler) ListAccounts(c *gin.Context) {
+	p, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var tokens []models.UserToken
+	if err := h.DB.Where("user_id = ? AND platform = ?", userID, p.Name()).Order("created_at").Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list accounts"})
+		return
+	}
+
+	accounts := make([]gin.H, 0, len(tokens))
+	for i := range tokens {
+		t := &tokens[i]
+		accounts = append(accounts, gin.H{
+			"account_id":   t.PlatformAccountID(),
+			"platform":     t.Platform,
+			"status":       t.Status,
+			"scope":        t.Scope,
+			"expires_at":   t.ExpiresAt,
+			"connected_at": t.CreatedAt,
+			"updated_at":   t.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+}
+
+// 토큰 교환 후 계정 ID를 얻기 위한 프로필 조회 횟수
+const profileAttempts = 2
+
+// 계정 이전(link_conflict: transfer)으로 다른 사용자의 연결을 해제한 기록
+func recordTransfer(c *gin.Context, userID, accountID string, previous []models.UserToken) {
+	store := audit.Default()
+	if store == nil {
+		return
+	}
+	previousUsers := make([]string, 0, len(previous))
+	for _, t := range previous {
+		previousUsers = append(previousUsers, t.UserID)
+	}
+	audit.AddParam(c, "account_id", accountID)
+	audit.AddParam(c, "transferred_from", previousUsers)
+	audit.SetAffected(c, int64(len(previous)))
+
+	entry := audit.Entry(c)
+	entry.Actor = userID
+	entry.Status = http.StatusOK
+	entry.Result = models.AuditResultSuccess
+	if err := store.Record(entry); err != nil {
+		log.Printf("❌ 감사 기록 저장 실패 (%s, request_id=%s): %v", entry.Action, entry.RequestID, err)
+	}
+}
+
+// 연결 충돌 정책 (oauth.link_conflict, 기본 deny)
+func linkConflictPolicy() string {
+	if cfg := config.Current(); cfg != nil && cfg.OAuth.LinkConflict == "transfer" {
+		return "transfer"
+	}
+	return "deny"
+}
+
+// 로그인 후 돌아갈 앱 주소 (oauth.redirect_targets에 등록된 이름, 비어 있으면 기본 대상)
+func appCallbackURL(platform, target string) (string, error) {
+	if cfg := config.Current(); cfg == nil || len(cfg.OAuth.RedirectTargets) == 0 {
+		// redirect_targets 설정 이전 기본값
+		if target != "" {
+			return "", fmt.Errorf("redirect targets are not configured")
+		}
+		return "https://adfit.ai/#/auth/callback/" + platform, nil
+	}
+	return config.RedirectTargetURL(target, platform)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// 인증 URL 요청의 ?feature= / ?scopes= → 추가로 요청할 scope
+func requestedScopes(p providers.OAuthProvider, feature, scopes string) ([]string, error) {
+	if feature == "" && scopes == "" {
+		return nil, nil
+	}
+	upgrader, ok := p.(providers.ScopeUpgrader)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support scope upgrades", p.Name())
+	}
+
+	var requested []string
+	if feature != "" {
+		required := upgrader.RequiredScopes(feature)
+		if required == nil {
+			return nil, fmt.Errorf("unknown feature: %s", feature)
+		}
+		requested = append(requested, required...)
+	}
+	for _, scope := range providers.ParseScopes(scopes) {
+		if !upgrader.SupportedScope(scope) {
+			return nil, fmt.Errorf("unsupported scope: %s", scope)
+		}
+		requested = append(requested, scope)
+	}
+	return providers.ParseScopes(providers.MergeScopes("", strings.Join(requested, " "))), nil
+}
+
+// 기능에 필요한 scope를 연결된 계정이 모두 동의했는지 확인
+//
+// 부족하면 403 insufficient_scope와 함께 추가 동의를 받을 인증 URL(upgrade_url)을 반환합니다.
+func requireFeatureScopes(c *gin.Context, p providers.OAuthProvider, userToken *models.UserToken, feature string) bool {
+	upgrader, ok := p.(providers.ScopeUpgrader)
+	if !ok {
+		return true
+	}
+	required := upgrader.RequiredScopes(feature)
+	missing := providers.MissingScopes(userToken.Scope, required)
+	if len(missing) == 0 {
+		return true
+	}
+
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":       "insufficient_scope",
+		"message":     fmt.Sprintf("%s 권한에 추가 동의가 필요합니다", p.Name()),
+		"feature":     feature,
+		"required":    required,
+		"missing":     missing,
+		"granted":     providers.ParseScopes(userToken.Scope),
+		"account_id":  userToken.AccountID,
+		"upgrade_url": upgradeURL(c, p.Name(), feature),
+	})
+	return false
+}
+
+// 추가 동의용 인증 URL (GET /api/:provider/auth?feature=..., 같은 자격 증명으로 호출)
+func upgradeURL(c *gin.Context, platform, feature string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+
+	query := url.Values{}
+	query.Set("feature", feature)
+	return fmt.Sprintf("%s://%s/api/%s/auth?%s", scheme, c.Request.Host, platform, query.Encode())
+}
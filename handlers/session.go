@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/session"
+)
+
+// SessionHandler 세션 토큰 재발급/로그아웃 (/api/session/...)
+type SessionHandler struct {
+	Sessions *session.Manager
+}
+
+func NewSessionHandler(manager *session.Manager) *SessionHandler {
+	return &SessionHandler{Sessions: manager}
+}
+
+// Refresh refresh token으로 access JWT 재발급 (refresh token도 새 값으로 교체)
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.Sessions.Rotate(req.RefreshToken)
+	switch {
+	case err == nil:
+	case errors.Is(err, session.ErrRefreshInvalid),
+		errors.Is(err, session.ErrRefreshExpired),
+		errors.Is(err, session.ErrRefreshRevoked),
+		errors.Is(err, session.ErrRefreshReused):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_refresh_token", "details": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":            true,
+		"access_token":       tokens.AccessToken,
+		"expires_in":         int(tokens.AccessExpiresIn.Seconds()),
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_in": int(tokens.RefreshExpiresIn.Seconds()),
+	})
+}
+
+// Logout 현재 access JWT 거부 + refresh token 폐기 (플랫폼 연결은 유지)
+func (h *SessionHandler) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	c.ShouldBindJSON(&req)
+
+	// 인증된 사용자의 refresh token만 폐기 (다른 사용자의 토큰으로 남의 세션을 끊을 수 없음)
+	if req.RefreshToken != "" {
+		err := h.Sessions.RevokeOwnRefreshToken(req.RefreshToken, c.GetString("user_id"))
+		switch {
+		case errors.Is(err, session.ErrRefreshNotOwned):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Refresh token does not belong to the authenticated user"})
+			return
+		case err != nil && !errors.Is(err, session.ErrRefreshInvalid):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			return
+		}
+	}
+	if claims, ok := c.Get("session_claims"); ok {
+		if err := h.Sessions.Deny(claims.(*session.Claims)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
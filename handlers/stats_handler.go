@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	
+	"adfit-oauth/audit"
 	"adfit-oauth/services"
 )
 
@@ -23,19 +24,11 @@ func NewStatsHandler() (*StatsHandler, error) {
 	}, nil
 }
 
-// 모든 활성 대회 통계 업데이트 (수동 트리거)
+// 모든 활성 대회 통계 업데이트 (수동 트리거, stats:update 권한 필요)
 func (h *StatsHandler) UpdateAllActiveCompetitions(c *gin.Context) {
-	// 인증 확인 (간단한 토큰 방식)
-	token := c.GetHeader("Authorization")
-	if token != "Bearer adfit-stats-update-token" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Unauthorized",
-		})
-		return
-	}
-
 	err := h.statsService.UpdateAllActiveCompetitions()
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "통계 업데이트 실패",
 			"details": err.Error(),
@@ -49,7 +42,7 @@ func (h *StatsHandler) UpdateAllActiveCompetitions(c *gin.Context) {
 	})
 }
 
-// 특정 대회 통계 업데이트
+// 특정 대회 통계 업데이트 (stats:update 권한 필요)
 func (h *StatsHandler) UpdateCompetitionStats(c *gin.Context) {
 	competitionID := c.Param("id")
 	if competitionID == "" {
@@ -59,17 +52,9 @@ func (h *StatsHandler) UpdateCompetitionStats(c *gin.Context) {
 		return
 	}
 
-	// 인증 확인
-	token := c.GetHeader("Authorization")
-	if token != "Bearer adfit-stats-update-token" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Unauthorized",
-		})
-		return
-	}
-
 	err := h.statsService.UpdateCompetitionStats(competitionID)
 	if err != nil {
+		audit.SetError(c, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "대회 통계 업데이트 실패",
 			"details": err.Error(),
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/config"
+)
+
+// 콜백 응답 방식
+const (
+	responseModeRedirect   = ""            // 기본: 앱 주소로 307 리다이렉트
+	responseModeWebMessage = "web_message" // 팝업: opener에 postMessage 후 창 닫기
+)
+
+// 인증 요청의 response_mode / origin 검증 → state에 저장할 값
+func webMessageOptions(responseMode, origin string) (string, string, error) {
+	switch responseMode {
+	case responseModeRedirect:
+		return "", "", nil
+	case responseModeWebMessage:
+	default:
+		return "", "", fmt.Errorf("unsupported response_mode: %s", responseMode)
+	}
+
+	normalized, err := allowedWebOrigin(origin)
+	if err != nil {
+		return "", "", err
+	}
+	return responseMode, normalized, nil
+}
+
+// opener origin은 cors.allowed_origins에 정확히 등록된 것만 허용 ("*" 불가)
+func allowedWebOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("origin must be scheme://host[:port]: %q", origin)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("origin must be http(s): %q", origin)
+	}
+	normalized := strings.ToLower(u.Scheme + "://" + u.Host)
+
+	if cfg := config.Current(); cfg != nil {
+		for _, allowed := range cfg.CORS.AllowedOrigins {
+			if strings.ToLower(strings.TrimSuffix(allowed, "/")) == normalized {
+				return normalized, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("origin is not allowed: %q", origin)
+}
+
+// postMessage로 보내는 메시지 (opener는 event.origin과 type을 확인)
+type webMessage struct {
+	Type        string `json:"type"`
+	Provider    string `json:"provider"`
+	Code        string `json:"code,omitempty"`
+	State       string `json:"state"`
+	ClientState string `json:"client_state,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// 인라인 스크립트는 nonce로만 실행 (html/template이 JSON/문자열을 문맥에 맞게 이스케이프)
+var webMessageTemplate = template.Must(template.New("web_message").Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<meta name="referrer" content="no-referrer">
+<title>AdFit 로그인</title>
+</head>
+<body>
+<p id="status">로그인 처리 중...</p>
+<script nonce="{{.Nonce}}">
+(function () {
+  var message = {{.Message}};
+  var targetOrigin = {{.Origin}};
+  if (window.opener && !window.opener.closed) {
+    window.opener.postMessage(message, targetOrigin);
+    window.close();
+  }
+  document.getElementById("status").textContent = "이 창을 닫고 앱으로 돌아가세요.";
+})();
+</script>
+</body>
+</html>
+`))
+
+// opener 창에 code/state를 전달하는 HTML 페이지 응답
+func renderWebMessage(c *gin.Context, origin string, message webMessage) {
+	nonce, err := randomNonce()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render callback page")
+		return
+	}
+	message.Type = "adfit:oauth_callback"
+
+	c.Header("Content-Security-Policy", fmt.Sprintf(
+		"default-src 'none'; script-src 'nonce-%s'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'", nonce))
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	webMessageTemplate.Execute(c.Writer, gin.H{
+		"Nonce":   nonce,
+		"Message": message,
+		"Origin":  origin,
+	})
+}
+
+func randomNonce() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(b), nil
+}
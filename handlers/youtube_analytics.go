@@ -3,11 +3,9 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 	"google.golang.org/api/youtubeanalytics/v2"
@@ -19,54 +17,22 @@ import (
 func (h *YouTubeHandler) GetVideoAnalytics(c *gin.Context) {
 	videoID := c.Param("videoId")
 	
-	// Authorization 헤더에서 토큰 추출
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(401, gin.H{"error": "No authorization header"})
+	// 사용자 확인 (AuthRequired 미들웨어에서 설정)
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(401, gin.H{"error": "Unauthorized - no valid session"})
 		return
 	}
-	
-	sessionToken := strings.Replace(authHeader, "Bearer ", "", 1)
-	
-	// 세션 토큰으로 사용자 확인
+
 	var userToken models.UserToken
-	if err := h.DB.Where("access_token = ? AND platform = ?", sessionToken, "youtube").First(&userToken).Error; err != nil {
-		// JWT 토큰일 수도 있으므로 user_id로 다시 검색
-		userID := c.GetString("user_id")
-		if userID == "" {
-			c.JSON(401, gin.H{"error": "Unauthorized - no valid session"})
-			return
-		}
-		
-		if err := h.DB.Where("user_id = ? AND platform = ?", userID, "youtube").First(&userToken).Error; err != nil {
-			c.JSON(401, gin.H{"error": "YouTube not connected"})
-			return
-		}
-	}
-	
-	// OAuth 토큰 복원
-	token := &oauth2.Token{
-		AccessToken:  userToken.AccessToken,
-		RefreshToken: userToken.RefreshToken,
-		TokenType:    "Bearer",
-		Expiry:       userToken.ExpiresAt,
-	}
-	
-	// 토큰 만료 체크 및 갱신
-	if token.Expiry.Before(time.Now()) && userToken.RefreshToken != "" {
-		newToken, err := h.oauth2Config.TokenSource(context.Background(), token).Token()
-		if err == nil {
-			token = newToken
-			// DB 업데이트
-			userToken.AccessToken = newToken.AccessToken
-			userToken.ExpiresAt = newToken.Expiry
-			h.DB.Save(&userToken)
-		}
+	if err := userTokenQuery(h.DB, c, userID, "youtube").First(&userToken).Error; err != nil {
+		c.JSON(401, gin.H{"error": "YouTube not connected"})
+		return
 	}
 	
-	// YouTube 서비스 초기화
+	// YouTube 서비스 초기화 (만료된 토큰은 갱신 후 DB에 저장)
 	ctx := context.Background()
-	client := h.oauth2Config.Client(ctx, token)
+	client := h.client(ctx, &userToken)
 	
 	// 먼저 YouTube Data API로 기본 정보 가져오기
 	youtubeService, err := youtube.NewService(ctx, option.WithHTTPClient(client))
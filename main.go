@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 	
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -12,11 +15,18 @@ import (
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 	
+	"adfit-oauth/apikeys"
+	"adfit-oauth/audit"
 	"adfit-oauth/config"
+	"adfit-oauth/encryption"
+	"adfit-oauth/features"
 	"adfit-oauth/handlers"
 	"adfit-oauth/middleware"
 	"adfit-oauth/models"
+	"adfit-oauth/providers"
+	"adfit-oauth/ratelimit"
 	"adfit-oauth/services"
+	"adfit-oauth/session"
 )
 
 func main() {
@@ -26,6 +36,22 @@ func main() {
 		// 기본 설정으로 계속 진행
 	}
 
+	// 관리 명령 실행 (예: go run . reencrypt-tokens)
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
+	// 설정 검증 (운영 환경에서는 문제가 하나라도 있으면 시작하지 않음)
+	if err := config.Validate(config.Current()); err != nil {
+		if config.IsProduction() {
+			log.Fatalf("❌ %v", err)
+		}
+		log.Printf("⚠️ %v", err)
+	}
+
 	// 데이터베이스 초기화
 	db, err := initDatabase()
 	if err != nil {
@@ -33,16 +59,34 @@ func main() {
 	}
 
 	// Gin 엔진 설정
-	if config.Config != nil && !config.IsDebugMode() {
+	if config.Current() != nil && !config.IsDebugMode() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.Default()
+	// X-Forwarded-For는 등록된 앞단 프록시만 신뢰 (헤더 위조로 IP별 요청 제한 우회 방지)
+	var trustedProxies []string
+	if cfg := config.Current(); cfg != nil {
+		trustedProxies = cfg.Security.TrustedProxies
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("❌ security.trusted_proxies 설정 실패: %v", err)
+	}
+	r.Use(middleware.RequestID())
 
 	// CORS 설정
 	setupCORS(r)
 
+	// 요청 제한 (security.rate_limit)
+	if err := services.InitRateLimiter(); err != nil {
+		log.Fatalf("❌ 요청 제한 초기화 실패: %v", err)
+	}
+	config.OnReload("rate_limit", services.ApplyRateLimit)
+
+	// OAuth 프로바이더 초기화
+	registry := initProviders()
+
 	// 핸들러 초기화
-	setupHandlers(r, db)
+	setupHandlers(r, db, registry)
 
 	// 헬스 체크
 	r.GET("/health", func(c *gin.Context) {
@@ -54,27 +98,33 @@ func main() {
 			},
 		}
 		
-		if config.Config != nil {
-			response["app"] = config.Config.App.Name
-			response["version"] = config.Config.App.Version
-			response["environment"] = config.Config.App.Environment
+		// 설정은 재로드 중에 교체되므로 Current()로 읽음
+		if cfg := config.Current(); cfg != nil {
+			response["app"] = cfg.App.Name
+			response["version"] = cfg.App.Version
+			response["environment"] = cfg.App.Environment
 		}
 		
 		c.JSON(200, response)
 	})
 
-	// Cron 작업 시작 (설정이 있고 활성화되어 있을 때만)
-	if config.Config != nil && config.IsFeatureEnabled("cron") {
-		go startCronJobs()
+	// Cron 작업 시작 (설정이 있을 때만, features.cron_enabled는 재로드 시에도 반영)
+	if config.Current() != nil {
+		go startCronJobs(db, registry)
+	}
+
+	// 설정 파일 변경/SIGHUP 시 재로드 (CORS, 크론 스케줄, 요청 제한, 기능 플래그)
+	if config.Current() != nil {
+		go config.Watch(context.Background(), 5*time.Second)
 	}
 
 	// 서버 시작
 	port := getPort()
 	log.Printf("🚀 AdFit OAuth Server 시작 (포트: %s)", port)
 	
-	if config.Config != nil {
-		log.Printf("   앱: %s v%s", config.Config.App.Name, config.Config.App.Version)
-		log.Printf("   환경: %s", config.Config.App.Environment)
+	if cfg := config.Current(); cfg != nil {
+		log.Printf("   앱: %s v%s", cfg.App.Name, cfg.App.Version)
+		log.Printf("   환경: %s", cfg.App.Environment)
 	}
 	
 	if err := r.Run(":" + port); err != nil {
@@ -90,7 +140,7 @@ func getPort() string {
 	}
 	
 	// 2. config에서
-	if config.Config != nil {
+	if config.Current() != nil {
 		return config.GetPort()
 	}
 	
@@ -102,7 +152,7 @@ func getPort() string {
 func initDatabase() (*gorm.DB, error) {
 	var dbPath string
 	
-	if config.Config != nil {
+	if config.Current() != nil {
 		dbPath = config.GetDatabasePath()
 	} else {
 		dbPath = "adfit.db"
@@ -113,10 +163,59 @@ func initDatabase() (*gorm.DB, error) {
 		return nil, err
 	}
 	
+	// 연결 계정 고유 인덱스를 만들기 전에 이전 데이터 정리 (계정 ID 채우기 + 중복 연결 해제)
+	if err := services.PrepareUserTokens(db); err != nil {
+		return nil, err
+	}
+
 	// 테이블 자동 생성
-	if err := db.AutoMigrate(&models.UserToken{}); err != nil {
+	if err := db.AutoMigrate(&models.UserToken{}, &models.OAuthState{}, &models.PendingRevocation{}, &models.SessionRefreshToken{}, &models.RevokedSession{}, &models.AdminAccount{}, &models.APIKey{}, &models.FeatureOverride{}, &models.FeatureCohortMember{}); err != nil {
+		return nil, err
+	}
+
+	// 감사 기록 (추가만 가능)
+	if err := audit.Migrate(db); err != nil {
+		return nil, err
+	}
+	audit.SetDefault(audit.NewStore(db))
+
+	// 기능 플래그 런타임 오버라이드 (환경/코호트별, 설정 파일보다 우선)
+	featureStore := features.NewStore(db)
+	if err := featureStore.Refresh(); err != nil {
+		return nil, err
+	}
+	features.SetDefault(featureStore)
+
+	// 토큰 암호화 초기화 + 기존 평문 토큰 암호화
+	if err := services.InitTokenEncryption(); err != nil {
+		return nil, err
+	}
+	if encryption.Default() != nil {
+		migrated, err := services.ReencryptTokens(db, true)
+		if err != nil {
+			return nil, err
+		}
+		if migrated > 0 {
+			log.Printf("🔐 평문 토큰 %d개 암호화 완료", migrated)
+		}
+	}
+	
+	// 세션 JWT 서명 키 + refresh token 저장소
+	if err := services.InitSessions(db); err != nil {
 		return nil, err
 	}
+
+	// 기계 호출용 API 키 (기존 stats.update_token은 allow_legacy_token일 때만 stats:update 권한으로 허용)
+	legacyStatsToken := ""
+	if cfg := config.Current(); cfg != nil && cfg.Stats.UpdateToken != "" {
+		if cfg.Stats.AllowLegacyToken {
+			legacyStatsToken = cfg.Stats.UpdateToken
+			log.Printf("⚠️ stats.update_token 허용 중 (allow_legacy_token) - /api/admin/api-keys에서 API 키를 발급해 교체하세요")
+		} else {
+			log.Printf("⚠️ stats.update_token이 설정되어 있지만 allow_legacy_token이 false라서 무시합니다")
+		}
+	}
+	apikeys.SetDefault(apikeys.NewStore(db, legacyStatsToken))
 	
 	log.Printf("✅ 데이터베이스 연결 완료: %s", dbPath)
 	return db, nil
@@ -124,19 +223,22 @@ func initDatabase() (*gorm.DB, error) {
 
 // CORS 설정
 func setupCORS(r *gin.Engine) {
-	var corsConfig cors.Config
-	
-	if config.Config != nil {
-		corsConfig = cors.Config{
-			AllowOrigins:     config.Config.CORS.AllowedOrigins,
-			AllowMethods:     config.Config.CORS.AllowedMethods,
-			AllowHeaders:     config.Config.CORS.AllowedHeaders,
-			ExposeHeaders:    config.Config.CORS.ExposeHeaders,
-			AllowCredentials: config.Config.CORS.AllowCredentials,
-		}
-	} else {
+	if err := middleware.SetCORS(corsConfig(config.Current())); err != nil {
+		log.Fatalf("❌ CORS 설정 실패: %v", err)
+	}
+	r.Use(middleware.CORS())
+
+	// 설정 재로드 시 cors.* 반영
+	config.OnReload("cors", func(cfg *config.AppConfig) error {
+		return middleware.SetCORS(corsConfig(cfg))
+	})
+	log.Printf("✅ CORS 설정 완료")
+}
+
+func corsConfig(cfg *config.AppConfig) cors.Config {
+	if cfg == nil {
 		// 기본 CORS 설정
-		corsConfig = cors.Config{
+		return cors.Config{
 			AllowOrigins:     []string{"*"},
 			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 			AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
@@ -144,77 +246,138 @@ func setupCORS(r *gin.Engine) {
 			AllowCredentials: false,
 		}
 	}
-	
-	r.Use(cors.New(corsConfig))
-	log.Printf("✅ CORS 설정 완료")
+	return cors.Config{
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
+		AllowMethods:     cfg.CORS.AllowedMethods,
+		AllowHeaders:     cfg.CORS.AllowedHeaders,
+		ExposeHeaders:    cfg.CORS.ExposeHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+	}
+}
+
+// OAuth 프로바이더 초기화
+func initProviders() *providers.Registry {
+	// 클라이언트 ID/시크릿/리다이렉트 주소는 모두 설정(oauth.*)에서
+	var oauthConfig config.OAuthConfig
+	if cfg := config.Current(); cfg != nil {
+		oauthConfig = cfg.OAuth
+	}
+	// 모두 등록하고 사용 여부는 요청마다 기능 플래그로 판단 (features.*, 관리자 오버라이드)
+	return providers.NewRegistry(providers.NewTikTok(oauthConfig.TikTok), providers.NewYouTube(oauthConfig.YouTube), providers.NewInstagram(oauthConfig.Instagram))
 }
 
 // 핸들러 설정
-func setupHandlers(r *gin.Engine, db *gorm.DB) {
-	// TikTok 핸들러 (항상 활성화)
-	setupTikTokRoutes(r, db)
+func setupHandlers(r *gin.Engine, db *gorm.DB, registry *providers.Registry) {
+	// 공통 OAuth 라우트 (/api/:provider/...)
+	setupOAuthRoutes(r, db, registry)
+	log.Printf("✅ OAuth API 라우트 활성화: %v", registry.Names())
+
+	// 세션 라우트 (/api/session/...)
+	setupSessionRoutes(r)
+
+	// 플랫폼/통계 라우트는 항상 등록, 꺼진 기능은 RequireFeature가 403 feature_disabled 응답
+
+	// TikTok 핸들러 (features.tiktok_enabled)
+	tiktokProvider, _ := registry.Get("tiktok")
+	setupTikTokRoutes(r, db, tiktokProvider.(*providers.TikTok))
 	log.Println("✅ TikTok API 라우트 활성화")
 	
-	// YouTube 핸들러 (항상 활성화)
-	setupYouTubeRoutes(r, db)
+	// YouTube 핸들러 (features.youtube_enabled, 분석은 features.analytics_enabled)
+	youtubeProvider, _ := registry.Get("youtube")
+	setupYouTubeRoutes(r, db, youtubeProvider.(*providers.YouTube))
 	log.Println("✅ YouTube API 라우트 활성화")
+
+	// Instagram 핸들러 (features.instagram_enabled)
+	instagramProvider, _ := registry.Get("instagram")
+	setupInstagramRoutes(r, db, instagramProvider.(*providers.Instagram))
+	log.Println("✅ Instagram API 라우트 활성화")
 	
-	// 통계 핸들러
-	if config.Config == nil || config.IsFeatureEnabled("stats") {
-		setupStatsRoutes(r)
-		log.Println("✅ 통계 API 라우트 활성화")
-	}
+	// 통계 핸들러 (features.stats_enabled)
+	setupStatsRoutes(r)
+	log.Println("✅ 통계 API 라우트 활성화")
 	
 	// 관리자 핸들러
-	setupAdminRoutes(r)
+	setupAdminRoutes(r, db, registry)
 	log.Println("✅ 관리자 API 라우트 활성화")
 }
 
-// TikTok 라우트 설정
-func setupTikTokRoutes(r *gin.Engine, db *gorm.DB) {
-	tiktokHandler := &handlers.TikTokHandler{DB: db}
-	
-	// 공개 라우트
-	public := r.Group("/api/tiktok")
+// 공통 OAuth 라우트 설정
+func setupSessionRoutes(r *gin.Engine) {
+	sessionHandler := handlers.NewSessionHandler(session.Default())
+
+	r.POST("/api/session/refresh", middleware.RateLimit(ratelimit.BudgetOAuth), sessionHandler.Refresh)
+	r.POST("/api/session/logout", middleware.AuthRequired(), middleware.RateLimit(ratelimit.BudgetDefault), sessionHandler.Logout)
+}
+
+func setupOAuthRoutes(r *gin.Engine, db *gorm.DB, registry *providers.Registry) {
+	oauthHandler := handlers.NewOAuthHandler(db, registry)
+
+	// 인증 시작 (IP별 요청 제한, state를 인증된 사용자에게 바인딩)
+	r.GET("/api/:provider/auth", middleware.RateLimit(ratelimit.BudgetOAuth), middleware.AuthRequired(), middleware.RequireProviderFeature(), middleware.RequireScopes("accounts"), oauthHandler.GetAuthURL)
+
+	// 공개 라우트 (플랫폼이 리다이렉트하는 콜백, IP별 요청 제한)
+	// 사용자를 알 수 없으므로 기능 플래그는 /auth와 /token에서 사용자(코호트) 기준으로 확인
+	public := r.Group("/api/:provider")
+	public.Use(middleware.RateLimit(ratelimit.BudgetOAuth))
 	{
-		public.GET("/auth", tiktokHandler.GetAuthURL)
-		public.GET("/callback", tiktokHandler.HandleCallback)
-		public.POST("/token", tiktokHandler.ExchangeToken)
+		public.GET("/callback", oauthHandler.HandleCallback)
+	}
+
+	// 인증 필요 라우트
+	protected := r.Group("/api/:provider")
+	protected.Use(middleware.AuthRequired(), middleware.RequireProviderFeature(), middleware.RateLimit(ratelimit.BudgetDefault), middleware.RequireScopes("accounts"))
+	{
+		protected.POST("/token", oauthHandler.ExchangeToken) // 인증된 사용자에게 계정 연결
+		protected.POST("/refresh", oauthHandler.RefreshToken)
+		protected.POST("/logout", oauthHandler.Logout)
+		protected.GET("/accounts", oauthHandler.ListAccounts)
+	}
+}
+
+// TikTok 라우트 설정
+func setupTikTokRoutes(r *gin.Engine, db *gorm.DB, provider *providers.TikTok) {
+	tiktokHandler := &handlers.TikTokHandler{
+		DB:       db,
+		Provider: provider,
 	}
 	
 	// 인증 필요 라우트
 	protected := r.Group("/api/tiktok")
-	protected.Use(middleware.AuthRequired())
+	protected.Use(middleware.AuthRequired(), middleware.RequireFeature(features.TikTok), middleware.RateLimit(ratelimit.BudgetDefault), middleware.RequireScopes("videos"))
 	{
 		protected.GET("/user", tiktokHandler.GetUserInfo)
 		protected.GET("/videos", tiktokHandler.GetVideos)
-		protected.POST("/refresh", tiktokHandler.RefreshToken)
-		protected.POST("/logout", tiktokHandler.Logout)
 	}
 }
 
 // YouTube 라우트 설정
-func setupYouTubeRoutes(r *gin.Engine, db *gorm.DB) {
-	youtubeHandler := handlers.NewYouTubeHandler(db)
-	
-	// 공개 라우트
-	youtubePublic := r.Group("/api/youtube")
-	{
-		youtubePublic.GET("/auth", youtubeHandler.GetAuthURL)
-		youtubePublic.GET("/callback", youtubeHandler.HandleCallback)
-		youtubePublic.POST("/token", youtubeHandler.ExchangeToken)
-	}
+func setupYouTubeRoutes(r *gin.Engine, db *gorm.DB, provider *providers.YouTube) {
+	youtubeHandler := handlers.NewYouTubeHandler(db, provider)
 	
 	// 인증 필요 라우트
 	youtubeProtected := r.Group("/api/youtube")
-	youtubeProtected.Use(middleware.AuthRequired())
+	youtubeProtected.Use(middleware.AuthRequired(), middleware.RequireFeature(features.YouTube), middleware.RateLimit(ratelimit.BudgetDefault), middleware.RequireScopes("videos"))
 	{
 		youtubeProtected.GET("/user", youtubeHandler.GetUserInfo)
 		youtubeProtected.GET("/channel", youtubeHandler.GetChannelInfo)
 		youtubeProtected.GET("/videos", youtubeHandler.GetVideos)
-		youtubeProtected.GET("/analytics/:videoId", youtubeHandler.GetVideoAnalytics)
-		youtubeProtected.POST("/refresh", youtubeHandler.RefreshToken)
-		youtubeProtected.POST("/logout", youtubeHandler.Logout)
+		youtubeProtected.GET("/analytics/:videoId", middleware.RequireFeature(features.Analytics), middleware.RequireScopes("analytics"), youtubeHandler.GetVideoAnalytics)
+	}
+}
+
+// Instagram 라우트 설정
+func setupInstagramRoutes(r *gin.Engine, db *gorm.DB, provider *providers.Instagram) {
+	instagramHandler := &handlers.InstagramHandler{
+		DB:       db,
+		Provider: provider,
+	}
+
+	// 인증 필요 라우트
+	protected := r.Group("/api/instagram")
+	protected.Use(middleware.AuthRequired(), middleware.RequireFeature(features.Instagram), middleware.RateLimit(ratelimit.BudgetDefault), middleware.RequireScopes("videos"))
+	{
+		protected.GET("/user", instagramHandler.GetUserInfo)
+		protected.GET("/videos", instagramHandler.GetVideos)
 	}
 }
 
@@ -226,111 +389,247 @@ func setupStatsRoutes(r *gin.Engine) {
 		return
 	}
 
+	// 통계 업데이트는 stats:update 권한의 API 키 필요 (통계 예산으로 요청 제한)
+	statsUpdate := []gin.HandlerFunc{middleware.AuthRequired(), middleware.RequireFeature(features.Stats), middleware.RateLimit(ratelimit.BudgetStats), middleware.Audit(), middleware.RequireScopes("stats:update")}
+
 	statsGroup := r.Group("/api/stats")
 	{
-		statsGroup.GET("/health", statsHandler.GetStatsStatus)
-		statsGroup.POST("/update/all", statsHandler.UpdateAllActiveCompetitions)
-		statsGroup.POST("/update/competition/:id", statsHandler.UpdateCompetitionStats)
+		statsGroup.GET("/health", middleware.RequireFeature(features.Stats), statsHandler.GetStatsStatus)
+		statsGroup.POST("/update/all", append(statsUpdate, statsHandler.UpdateAllActiveCompetitions)...)
+		statsGroup.POST("/update/competition/:id", append(statsUpdate, statsHandler.UpdateCompetitionStats)...)
 	}
 }
 
 // 관리자 라우트 설정
-func setupAdminRoutes(r *gin.Engine) {
+func setupAdminRoutes(r *gin.Engine, db *gorm.DB, registry *providers.Registry) {
+	adminAuthHandler := handlers.NewAdminAuthHandler(db, session.Default())
+	accountsHandler := handlers.NewAdminAccountsHandler(db, services.NewRevocationService(db, registry))
+	apiKeysHandler := handlers.NewAdminAPIKeysHandler(apikeys.Default())
+	auditHandler := handlers.NewAdminAuditHandler(audit.Default())
+	featuresHandler := handlers.NewAdminFeaturesHandler(features.Default())
+
+	// 관리자 로그인 (SQLite 관리자 계정)
+	r.POST("/api/admin/login", middleware.RateLimit(ratelimit.BudgetOAuth), adminAuthHandler.Login)
+
+	// 관리자 API 그룹 (라우트별 권한, 괄호 안은 API 키로 호출할 때 필요한 scope)
+	viewer := middleware.RequireRole(models.AdminRoleViewer)
+	operator := middleware.RequireRole(models.AdminRoleOperator)
+	superadmin := middleware.RequireRole(models.AdminRoleSuperadmin)
+	read := middleware.RequireRole(models.AdminRoleViewer, "admin:read")
+	trigger := middleware.RequireRole(models.AdminRoleOperator, "admin:trigger")
+	triggerLimit := middleware.RateLimit(ratelimit.BudgetStats)
+	cleanup := middleware.RequireRole(models.AdminRoleSuperadmin, "admin:cleanup")
+
+	adminGroup := r.Group("/api/admin")
+	adminGroup.Use(middleware.AuthRequired(), middleware.RateLimit(ratelimit.BudgetDefault), middleware.Audit())
+	{
+		// 연결 계정 (플랫폼 권한 취소)
+		adminGroup.POST("/users/:user_id/revoke-all", operator, accountsHandler.RevokeAllForUser)
+		adminGroup.GET("/revocations", viewer, accountsHandler.GetPendingRevocations)
+
+		// 관리자 계정
+		adminGroup.GET("/admins", superadmin, adminAuthHandler.ListAdmins)
+		adminGroup.POST("/admins", superadmin, adminAuthHandler.CreateAdmin)
+		adminGroup.PATCH("/admins/:id", superadmin, adminAuthHandler.UpdateAdmin)
+
+		// 감사 기록 (?format=csv 내보내기)
+		adminGroup.GET("/audit", read, auditHandler.GetAuditLogs)
+
+		// API 키
+		adminGroup.GET("/api-keys", superadmin, apiKeysHandler.ListAPIKeys)
+		adminGroup.POST("/api-keys", superadmin, apiKeysHandler.CreateAPIKey)
+		adminGroup.DELETE("/api-keys/:id", superadmin, apiKeysHandler.RevokeAPIKey)
+
+		// 기능 플래그 오버라이드 (환경/코호트별, 설정 파일보다 우선)
+		adminGroup.GET("/features", read, featuresHandler.ListFeatures)
+		adminGroup.PUT("/features/:feature", superadmin, featuresHandler.SetFeatureOverride)
+		adminGroup.DELETE("/features/:feature", superadmin, featuresHandler.DeleteFeatureOverride)
+		adminGroup.GET("/feature-cohorts/:cohort", read, featuresHandler.ListCohortMembers)
+		adminGroup.POST("/feature-cohorts/:cohort/members", superadmin, featuresHandler.AddCohortMembers)
+		adminGroup.DELETE("/feature-cohorts/:cohort/members/:user_id", superadmin, featuresHandler.RemoveCohortMember)
+	}
+
 	adminHandler, err := handlers.NewAdminStatsHandler()
 	if err != nil {
 		log.Printf("⚠️ AdminStatsHandler 초기화 실패: %v", err)
 		return
 	}
-
-	// 관리자 API 그룹 (인증 필요)
-	adminGroup := r.Group("/api/admin")
-	adminGroup.Use(adminHandler.AdminAuthRequired())
 	{
 		// 저장소 통계
-		adminGroup.GET("/storage/stats", adminHandler.GetStorageStats)
-		adminGroup.GET("/storage/backup-info", adminHandler.GetBackupInfo)
+		adminGroup.GET("/storage/stats", read, adminHandler.GetStorageStats)
+		adminGroup.GET("/storage/backup-info", read, adminHandler.GetBackupInfo)
 		
 		// 데이터 정리
-		adminGroup.DELETE("/cleanup/old-snapshots", adminHandler.CleanupOldSnapshots)
-		adminGroup.DELETE("/cleanup/date-range", adminHandler.DeleteDataByDateRange)
-		adminGroup.DELETE("/cleanup/competition/:id", adminHandler.DeleteCompetitionHistory)
+		adminGroup.DELETE("/cleanup/old-snapshots", cleanup, adminHandler.CleanupOldSnapshots)
+		adminGroup.DELETE("/cleanup/date-range", cleanup, adminHandler.DeleteDataByDateRange)
+		adminGroup.DELETE("/cleanup/competition/:id", cleanup, adminHandler.DeleteCompetitionHistory)
 		
 		// 수동 실행
-		adminGroup.POST("/trigger/daily-aggregation", adminHandler.TriggerDailyAggregation)
-		adminGroup.POST("/trigger/hourly-snapshots", adminHandler.TriggerHourlySnapshots)
+		adminGroup.POST("/trigger/daily-aggregation", trigger, triggerLimit, adminHandler.TriggerDailyAggregation)
+		adminGroup.POST("/trigger/hourly-snapshots", trigger, triggerLimit, adminHandler.TriggerHourlySnapshots)
 		
 		// 시스템 상태
-		adminGroup.GET("/system/health", adminHandler.GetSystemHealth)
+		adminGroup.GET("/system/health", read, adminHandler.GetSystemHealth)
 	}
 }
 
-// Cron 작업 시작
-func startCronJobs() {
+// Cron 작업 시작 (features.cron_enabled / cron.schedules 변경은 설정 재로드 시, 관리자 오버라이드는 즉시 반영)
+func startCronJobs(db *gorm.DB, registry *providers.Registry) {
 	log.Println("🕐 Cron 작업 스케줄러 시작 중...")
 
+	// 연결 계정 토큰 자동 갱신 (만료 전 미리 갱신, 거부되면 needs_reconnect 표시)
+	refresher := services.NewTokenRefresher(db, registry)
+	// 실패한 플랫폼 권한 취소 재시도
+	revocations := services.NewRevocationService(db, registry)
+
+	jobs := []cronJob{
+		{name: "token_refresh", label: "토큰 갱신", defaultSchedule: "0 */10 * * * *", run: func() { // 10분마다
+			log.Println("⏰ 연결 계정 토큰 갱신 시작")
+			result, err := refresher.RefreshExpiring(context.Background())
+			if err != nil {
+				log.Printf("❌ 토큰 갱신 작업 실패: %v", err)
+				return
+			}
+			log.Printf("✅ 토큰 갱신 완료 (갱신: %d, 재연결 필요: %d, 실패: %d, 다음 실행으로 미룸: %d)",
+				result.Refreshed, result.NeedsReconnect, result.Failed, result.Deferred)
+		}},
+		{name: "revocation_retry", label: "권한 취소 재시도", defaultSchedule: "0 */15 * * * *", run: func() { // 15분마다
+			revoked, failed, err := revocations.RetryPending(context.Background())
+			if err != nil {
+				log.Printf("❌ 권한 취소 재시도 실패: %v", err)
+				return
+			}
+			if revoked > 0 || failed > 0 {
+				log.Printf("✅ 권한 취소 재시도 완료 (성공: %d, 실패: %d)", revoked, failed)
+			}
+		}},
+		// 만료된 세션 refresh token / jti 거부 목록 정리
+		{name: "session_cleanup", label: "세션 정리", defaultSchedule: "0 0 4 * * *", run: func() { // 매일 오전 4시
+			purged, err := session.Default().Purge()
+			if err != nil {
+				log.Printf("❌ 세션 정리 실패: %v", err)
+				return
+			}
+			log.Printf("✅ 만료된 세션 %d개 정리 완료", purged)
+		}},
+	}
+
 	// StatsService 초기화
 	statsService, err := services.NewStatsService()
 	if err != nil {
 		log.Printf("❌ Cron용 StatsService 초기화 실패: %v", err)
-		return
-	}
-
-	// 크론 스케줄러 생성
-	c := cron.New(cron.WithSeconds())
-
-	// 매시간 통계 업데이트
-	schedule := "0 0 * * * *" // 매시간 정각
-	if config.Config != nil {
-		if s, exists := config.GetCronSchedule("hourly_stats"); exists {
-			schedule = s
-		}
-	}
-	
-	_, err = c.AddFunc(schedule, func() {
-		log.Println("⏰ [매시간] 활성 대회 통계 업데이트 시작")
-		if err := statsService.UpdateAllActiveCompetitions(); err != nil {
-			log.Printf("❌ 활성 대회 통계 업데이트 실패: %v", err)
-		} else {
-			log.Println("✅ [매시간] 활성 대회 통계 업데이트 완료")
-		}
-	})
-	if err != nil {
-		log.Printf("❌ 매시간 크론잡 등록 실패: %v", err)
-		return
+	} else {
+		jobs = append(jobs, statsCronJobs(statsService)...)
 	}
-	log.Printf("📅 매시간 통계 업데이트 스케줄 등록: %s", schedule)
 
-	// 일별 시스템 통계 업데이트
-	dailySchedule := "0 0 2 * * *" // 매일 새벽 2시
-	if config.Config != nil {
-		if s, exists := config.GetCronSchedule("daily_stats"); exists {
-			dailySchedule = s
-		}
-	}
-	
-	_, err = c.AddFunc(dailySchedule, func() {
-		log.Println("⏰ [매일] 전체 시스템 통계 업데이트 시작")
-		if err := statsService.SaveDailyAggregation(); err != nil {
-			log.Printf("❌ 시스템 통계 업데이트 실패: %v", err)
-		} else {
-			log.Println("✅ [매일] 전체 시스템 통계 업데이트 완료")
-		}
+	// 크론 스케줄러 생성 + 시작 (이전 실행이 아직 끝나지 않았으면 이번 실행은 건너뜀)
+	scheduler := newCronScheduler(cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.PrintfLogger(log.Default())))), jobs)
+	scheduler.apply()
+	scheduler.cron.Start()
+	config.OnReload("cron", func(*config.AppConfig) error {
+		scheduler.apply()
+		return nil
 	})
-	if err != nil {
-		log.Printf("⚠️ 일별 크론잡 등록 실패: %v", err)
-	} else {
-		log.Printf("📅 일별 시스템 통계 스케줄 등록: %s", dailySchedule)
+	if store := features.Default(); store != nil {
+		store.OnChange(scheduler.apply)
 	}
-
-	// 스케줄러 시작
-	c.Start()
-	log.Printf("✅ Cron 작업 스케줄러 실행 중 (%d개 작업)", len(c.Entries()))
+	log.Printf("✅ Cron 작업 스케줄러 실행 중 (%d개 작업)", len(scheduler.cron.Entries()))
 
 	// 종료 신호 대기
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
-	
+
 	log.Println("🛑 Cron 작업 스케줄러 종료 중...")
-	c.Stop()
+	scheduler.cron.Stop()
+}
+
+// 통계 크론잡
+func statsCronJobs(statsService *services.StatsService) []cronJob {
+	return []cronJob{
+		{name: "hourly_stats", label: "매시간 통계 업데이트", defaultSchedule: "0 0 * * * *", run: func() { // 매시간 정각
+			log.Println("⏰ [매시간] 활성 대회 통계 업데이트 시작")
+			if err := statsService.UpdateAllActiveCompetitions(); err != nil {
+				log.Printf("❌ 활성 대회 통계 업데이트 실패: %v", err)
+			} else {
+				log.Println("✅ [매시간] 활성 대회 통계 업데이트 완료")
+			}
+		}},
+		{name: "daily_stats", label: "일별 시스템 통계", defaultSchedule: "0 0 2 * * *", run: func() { // 매일 새벽 2시
+			log.Println("⏰ [매일] 전체 시스템 통계 업데이트 시작")
+			if err := statsService.SaveDailyAggregation(); err != nil {
+				log.Printf("❌ 시스템 통계 업데이트 실패: %v", err)
+			} else {
+				log.Println("✅ [매일] 전체 시스템 통계 업데이트 완료")
+			}
+		}},
+	}
+}
+
+// 크론 작업 (name은 cron.schedules의 키)
+type cronJob struct {
+	name            string
+	label           string
+	defaultSchedule string
+	run             func()
+}
+
+// cronScheduler 설정이 바뀌면 스케줄이 바뀐 작업만 다시 등록
+type cronScheduler struct {
+	mu      sync.Mutex
+	cron    *cron.Cron
+	jobs    []cronJob
+	entries map[string]cron.EntryID
+	specs   map[string]string
+}
+
+func newCronScheduler(c *cron.Cron, jobs []cronJob) *cronScheduler {
+	return &cronScheduler{
+		cron:    c,
+		jobs:    jobs,
+		entries: map[string]cron.EntryID{},
+		specs:   map[string]string{},
+	}
+}
+
+// apply 현재 설정 기준으로 작업 등록/교체/중지 (cron 기능 플래그가 꺼져 있으면 모두 중지)
+func (s *cronScheduler) apply() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enabled := features.Enabled(features.Cron, "")
+	for _, job := range s.jobs {
+		spec := ""
+		if enabled {
+			spec = job.defaultSchedule
+			if v, ok := config.GetCronSchedule(job.name); ok {
+				spec = v
+			}
+		}
+		if s.specs[job.name] == spec {
+			continue
+		}
+
+		if id, ok := s.entries[job.name]; ok {
+			s.cron.Remove(id)
+			delete(s.entries, job.name)
+			delete(s.specs, job.name)
+			if spec == "" {
+				log.Printf("⏸️ %s 크론잡 중지", job.label)
+				continue
+			}
+		}
+		if spec == "" {
+			continue
+		}
+
+		id, err := s.cron.AddFunc(spec, job.run)
+		if err != nil {
+			log.Printf("⚠️ %s 크론잡 등록 실패: %v", job.label, err)
+			continue
+		}
+		s.entries[job.name] = id
+		s.specs[job.name] = spec
+		log.Printf("📅 %s 스케줄 등록: %s", job.label, spec)
+	}
 }
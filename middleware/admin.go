@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/models"
+)
+
+// RequireRole 지정한 관리자 권한 이상만 허용 (AuthRequired 다음에 사용)
+//
+// 권한은 viewer < operator < superadmin 순서이며,
+// 관리자 계정 로그인 세션의 role 클레임 또는 Firebase 커스텀 클레임 admin_role에서 읽습니다.
+// apiKeyScopes를 지정하면 해당 scope를 모두 가진 API 키도 허용합니다.
+func RequireRole(role string, apiKeyScopes ...string) gin.HandlerFunc {
+	required := roleRank(role)
+	return func(c *gin.Context) {
+		auth, ok := GetAuthClaims(c)
+		if !ok {
+			abortUnauthorized(c, "Authentication required")
+			return
+		}
+
+		if auth.Source == AuthSourceAPIKey && len(apiKeyScopes) > 0 && hasAllScopes(auth, apiKeyScopes) {
+			c.Next()
+			return
+		}
+
+		if auth.Source == AuthSourceAPIKey || auth.Role == "" || roleRank(auth.Role) < required {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":         "관리자 권한이 필요합니다",
+				"code":          "ADMIN_ROLE_REQUIRED",
+				"required_role": role,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// 권한 순서 (알 수 없는 권한은 -1)
+func roleRank(role string) int {
+	for i, r := range models.AdminRoles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasAllScopes(auth *AuthClaims, scopes []string) bool {
+	for _, scope := range scopes {
+		if !auth.HasScope(scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/audit"
+)
+
+// Audit 변경 요청(GET/HEAD/OPTIONS 제외)을 감사 기록에 추가 (AuthRequired 다음, RequireRole 앞에 사용)
+//
+// 권한 부족으로 거부된 요청도 denied로 남습니다. 삭제 건수 등은 핸들러에서 audit.SetAffected로 전달합니다.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case "GET", "HEAD", "OPTIONS":
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		store := audit.Default()
+		if store == nil {
+			return
+		}
+		entry := audit.Entry(c)
+		if auth, ok := GetAuthClaims(c); ok {
+			entry.Actor = auth.UserID
+			entry.Source = auth.Source
+			entry.Role = auth.Role
+		}
+		if err := store.Record(entry); err != nil {
+			log.Printf("❌ 감사 기록 저장 실패 (%s, request_id=%s): %v", entry.Action, entry.RequestID, err)
+		}
+	}
+}
@@ -1,42 +1,223 @@
 package middleware
 
 import (
-    "net/http"
-    "strings"
-    "github.com/gin-gonic/gin"
-    "github.com/golang-jwt/jwt/v5"
-    "os"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"adfit-oauth/apikeys"
+	"adfit-oauth/session"
+)
+
+// gin context 키
+const authClaimsKey = "auth_claims"
+
+// AuthClaims 인증된 요청의 주체
+type AuthClaims struct {
+	UserID    string
+	Platform  string // 세션을 발급한 플랫폼 (없으면 "")
+	AccountID string // 세션을 발급한 플랫폼 계정
+	Scopes    []string
+	TokenID   string // jti
+	ExpiresAt time.Time
+	Source    string // "session", "firebase" 또는 "api_key"
+	Role      string // 관리자 권한 (관리자가 아니면 "")
+}
+
+// AuthClaims.Source
+const (
+	AuthSourceSession  = "session"
+	AuthSourceFirebase = "firebase"
+	AuthSourceAPIKey   = "api_key"
 )
 
+// HasScope 권한 보유 여부
+func (a *AuthClaims) HasScope(scope string) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAuthClaims AuthRequired가 설정한 인증 정보
+func GetAuthClaims(c *gin.Context) (*AuthClaims, bool) {
+	v, ok := c.Get(authClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*AuthClaims)
+	return claims, ok
+}
+
+// AuthRequired 세션 access JWT, Firebase ID token 또는 API 키 검증
+//
+// 세션 JWT는 설정된 알고리즘만 허용하고 iss/aud/exp/nbf를 허용 오차 안에서 검증합니다.
+// Firebase ID token(iss가 securetoken.google.com)은 Google JWKS로 검증하고 uid를 user_id로 사용하므로
+// 플랫폼을 연결하기 전에도 보호된 API를 호출할 수 있습니다.
+// API 키(Authorization: Bearer adk_... 또는 X-API-Key)는 키에 부여된 scope만 가집니다.
+// 검증된 클레임은 GetAuthClaims로, 사용자 ID는 기존처럼 c.GetString("user_id")로 읽습니다.
 func AuthRequired() gin.HandlerFunc {
-    return func(c *gin.Context) {
-        authHeader := c.GetHeader("Authorization")
-        if authHeader == "" {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "No authorization header"})
-            c.Abort()
-            return
-        }
-
-        tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-        
-        token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-            return []byte(os.Getenv("JWT_SECRET")), nil
-        })
-
-        if err != nil || !token.Valid {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-            c.Abort()
-            return
-        }
-
-        claims, ok := token.Claims.(jwt.MapClaims)
-        if !ok {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-            c.Abort()
-            return
-        }
-
-        c.Set("user_id", claims["user_id"])
-        c.Next()
-    }
+	return func(c *gin.Context) {
+		tokenString, ok := bearerToken(c)
+		if !ok {
+			abortUnauthorized(c, "No authorization header")
+			return
+		}
+
+		var auth *AuthClaims
+		var err error
+		if store := apikeys.Default(); store != nil && store.IsAPIKey(tokenString) {
+			auth, err = verifyAPIKey(c, store, tokenString)
+		} else if isFirebaseToken(tokenString) {
+			auth, err = verifyFirebase(c, tokenString)
+		} else {
+			auth, err = verifySession(c, tokenString)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, jwt.ErrTokenExpired), errors.Is(err, apikeys.ErrExpiredKey):
+				abortUnauthorized(c, "token_expired")
+			case errors.Is(err, session.ErrRevoked), errors.Is(err, apikeys.ErrRevokedKey):
+				abortUnauthorized(c, "token_revoked")
+			default:
+				abortUnauthorized(c, "Invalid token")
+			}
+			return
+		}
+		if auth.UserID == "" {
+			abortUnauthorized(c, "Invalid token claims")
+			return
+		}
+
+		c.Set(authClaimsKey, auth)
+		c.Set("user_id", auth.UserID)
+		c.Next()
+	}
+}
+
+func verifySession(c *gin.Context, tokenString string) (*AuthClaims, error) {
+	claims, err := session.Default().Verify(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	c.Set("session_claims", claims)
+
+	auth := &AuthClaims{
+		UserID:    claims.UserID,
+		Platform:  claims.Platform,
+		AccountID: claims.AccountID,
+		Scopes:    claims.Scopes(),
+		TokenID:   claims.ID,
+		Source:    AuthSourceSession,
+		Role:      claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		auth.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return auth, nil
+}
+
+func verifyFirebase(c *gin.Context, tokenString string) (*AuthClaims, error) {
+	verifier := session.Firebase()
+	if verifier == nil {
+		return nil, session.ErrFirebaseUnavailable
+	}
+	claims, err := verifier.Verify(c.Request.Context(), tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &AuthClaims{
+		UserID:  claims.Subject,
+		Scopes:  session.DefaultScopes,
+		TokenID: claims.ID,
+		Source:  AuthSourceFirebase,
+		Role:    claims.AdminRole,
+	}
+	if claims.ExpiresAt != nil {
+		auth.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return auth, nil
+}
+
+func verifyAPIKey(c *gin.Context, store *apikeys.Store, key string) (*AuthClaims, error) {
+	apiKey, err := store.Authenticate(key, c.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &AuthClaims{
+		UserID:  fmt.Sprintf("apikey:%s", apiKey.Prefix),
+		Scopes:  strings.Fields(apiKey.Scopes),
+		TokenID: apiKey.Prefix,
+		Source:  AuthSourceAPIKey,
+	}
+	if apiKey.ExpiresAt != nil {
+		auth.ExpiresAt = *apiKey.ExpiresAt
+	}
+	return auth, nil
+}
+
+// Firebase ID token 여부 (서명 검증 전 iss만 확인)
+func isFirebaseToken(tokenString string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+	iss, _ := claims["iss"].(string)
+	return strings.HasPrefix(iss, "https://securetoken.google.com/")
+}
+
+// RequireScopes 지정한 권한이 모두 있는 요청만 허용 (AuthRequired 다음에 사용)
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth, ok := GetAuthClaims(c)
+		if !ok {
+			abortUnauthorized(c, "Authentication required")
+			return
+		}
+
+		var missing []string
+		for _, scope := range scopes {
+			if !auth.HasScope(scope) {
+				missing = append(missing, scope)
+			}
+		}
+		if len(missing) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":    "insufficient_scope",
+				"required": scopes,
+				"missing":  missing,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Authorization: Bearer <token> (API 키는 X-API-Key 헤더도 허용)
+func bearerToken(c *gin.Context) (string, bool) {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key, true
+	}
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+	c.Abort()
 }
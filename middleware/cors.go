@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// 현재 CORS 처리기 (설정 재로드 시 SetCORS로 교체)
+var (
+	corsMu      sync.RWMutex
+	corsHandler gin.HandlerFunc
+)
+
+// CORS SetCORS로 설정된 CORS 처리 (설정 전이면 통과)
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		corsMu.RLock()
+		handler := corsHandler
+		corsMu.RUnlock()
+
+		if handler == nil {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
+}
+
+// SetCORS CORS 설정 교체 (잘못된 설정이면 오류, 기존 설정 유지)
+func SetCORS(cfg cors.Config) (err error) {
+	defer func() {
+		// cors.New는 잘못된 설정에 panic
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid CORS config: %v", r)
+		}
+	}()
+	handler := cors.New(cfg)
+
+	corsMu.Lock()
+	corsHandler = handler
+	corsMu.Unlock()
+	return nil
+}
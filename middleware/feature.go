@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/features"
+)
+
+// RequireFeature 기능 플래그가 꺼져 있으면 403 feature_disabled
+//
+// 요청마다 설정 파일(features.*)과 관리자 오버라이드(환경/코호트)를 확인합니다.
+// AuthRequired 다음에 두면 사용자의 코호트 오버라이드까지 적용됩니다.
+func RequireFeature(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !features.Enabled(feature, c.GetString("user_id")) {
+			featureDisabled(c, feature)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireProviderFeature /api/:provider 라우트용 (플랫폼 이름이 곧 기능 플래그)
+//
+// 코호트 오버라이드가 적용되도록 AuthRequired 다음에 둡니다 (인증 없는 콜백에는 사용하지 않음).
+func RequireProviderFeature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch feature := c.Param("provider"); feature {
+		case features.TikTok, features.YouTube, features.Instagram:
+			if !features.Enabled(feature, c.GetString("user_id")) {
+				featureDisabled(c, feature)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func featureDisabled(c *gin.Context, feature string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":   "feature_disabled",
+		"feature": feature,
+		"message": "This feature is currently disabled",
+	})
+}
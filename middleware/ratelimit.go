@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"adfit-oauth/ratelimit"
+)
+
+// RateLimit 토큰 버킷 요청 제한 (budget: ratelimit.BudgetDefault/BudgetOAuth/BudgetStats)
+//
+// AuthRequired 다음에 두면 API 키 또는 사용자별로, 공개 라우트에서는 클라이언트 IP별로 제한합니다.
+// 초과하면 429와 Retry-After를 반환하고, 저장소 오류 시에는 요청을 통과시킵니다.
+func RateLimit(budget string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := ratelimit.Default()
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		result, limited, err := limiter.Allow(c.Request.Context(), budget, rateLimitIdentity(c))
+		if err != nil {
+			log.Printf("⚠️ 요청 제한 확인 실패 (%s): %v", budget, err)
+			c.Next()
+			return
+		}
+		if !limited {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "rate_limited",
+				"message":     "요청이 너무 많습니다. 잠시 후 다시 시도하세요",
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// 제한 단위: API 키 > 사용자 > IP
+func rateLimitIdentity(c *gin.Context) string {
+	if auth, ok := GetAuthClaims(c); ok {
+		if auth.Source == AuthSourceAPIKey {
+			return "apikey:" + auth.TokenID
+		}
+		if auth.UserID != "" {
+			return "user:" + auth.UserID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
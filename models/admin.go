@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 관리자 권한 (아래로 갈수록 상위 권한)
+const (
+	AdminRoleViewer     = "viewer"     // 조회만 가능
+	AdminRoleOperator   = "operator"   // 수동 실행, 권한 취소
+	AdminRoleSuperadmin = "superadmin" // 데이터 삭제, 관리자 계정 관리
+)
+
+// AdminRoles 권한 순서 (낮은 권한 → 높은 권한)
+var AdminRoles = []string{AdminRoleViewer, AdminRoleOperator, AdminRoleSuperadmin}
+
+// AdminAccount 관리자 계정 (Firebase 커스텀 클레임 대신 사용하는 로컬 계정)
+type AdminAccount struct {
+	gorm.Model
+	Username     string `gorm:"uniqueIndex;not null"`
+	PasswordHash string `gorm:"not null"` // bcrypt
+	Role         string `gorm:"not null"`
+	Disabled     bool   `gorm:"not null;default:false"`
+	LastLoginAt  *time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey 기계 호출용 API 키 (Cloud Scheduler, 내부 도구)
+//
+// 원문은 발급 시 한 번만 보여 주고 SHA-256 해시만 저장합니다.
+type APIKey struct {
+	gorm.Model
+	Name       string     `gorm:"not null"`
+	Prefix     string     `gorm:"index;not null"` // 키 앞부분 (목록에서 구분용)
+	KeyHash    string     `gorm:"uniqueIndex;not null"`
+	Scopes     string     `gorm:"not null"` // 공백 구분 (stats:update admin:trigger ...)
+	CreatedBy  string     // 발급한 관리자
+	ExpiresAt  *time.Time `gorm:"index"`
+	LastUsedAt *time.Time
+	LastUsedIP string
+	RevokedAt  *time.Time
+}
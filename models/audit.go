@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AuditLog 관리자/파괴적 작업 감사 기록 (추가만 가능, UPDATE/DELETE는 트리거로 거부)
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	RequestID string    `gorm:"index" json:"request_id"`
+	Actor     string    `gorm:"index" json:"actor"`  // user_id (admin:<username>, apikey:<prefix>, Firebase uid)
+	Source    string    `json:"source"`              // session, firebase, api_key
+	Role      string    `json:"role"`                // 관리자 권한 (API 키는 비어 있음)
+	Action    string    `gorm:"index" json:"action"` // METHOD 라우트 (예: DELETE /api/admin/cleanup/date-range)
+	Endpoint  string    `json:"endpoint"`            // 실제 요청 경로
+	Params    string    `json:"params"`              // 경로/쿼리 파라미터 + 핸들러가 추가한 값 (JSON)
+	Affected  int64     `json:"affected"`            // 삭제/변경된 레코드 수
+	Result    string    `gorm:"index" json:"result"` // success, denied, failure
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	ClientIP  string    `json:"client_ip"`
+}
+
+// 감사 결과
+const (
+	AuditResultSuccess = "success"
+	AuditResultDenied  = "denied"
+	AuditResultFailure = "failure"
+)
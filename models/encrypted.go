@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+
+	"adfit-oauth/encryption"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// EncryptedSerializer `gorm:"serializer:encrypted"` 필드를 저장 시 암호화, 조회 시 복호화
+//
+// 암호화 키가 설정되지 않았으면 평문으로 저장하고,
+// 암호화 이전에 저장된 평문 값은 그대로 읽습니다.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("암호화 필드 %s: 지원하지 않는 타입 %T", field.Name, dbValue)
+	}
+
+	if encryption.IsEncrypted(value) {
+		keyring := encryption.Default()
+		if keyring == nil {
+			return fmt.Errorf("암호화 필드 %s: 암호화 키가 설정되지 않았습니다", field.Name)
+		}
+		plaintext, err := keyring.Decrypt(value)
+		if err != nil {
+			return fmt.Errorf("암호화 필드 %s: %v", field.Name, err)
+		}
+		value = plaintext
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, _ := fieldValue.(string)
+
+	keyring := encryption.Default()
+	if keyring == nil {
+		return value, nil
+	}
+	return keyring.Encrypt(value)
+}
+
+// 현재 암호화에 사용되는 키 ID (암호화 비활성화 시 "")
+func activeKeyID() string {
+	if keyring := encryption.Default(); keyring != nil {
+		return keyring.ActiveKeyID()
+	}
+	return ""
+}
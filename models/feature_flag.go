@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// FeatureOverride 기능 플래그 런타임 오버라이드 (관리자 API로 변경, 설정 파일보다 우선)
+//
+// Environment가 비어 있으면 모든 환경, Cohort가 비어 있으면 모든 사용자에게 적용됩니다.
+type FeatureOverride struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Feature     string    `gorm:"uniqueIndex:idx_feature_override;not null" json:"feature"` // tiktok, youtube, instagram, stats, cron, analytics
+	Environment string    `gorm:"uniqueIndex:idx_feature_override" json:"environment"`
+	Cohort      string    `gorm:"uniqueIndex:idx_feature_override" json:"cohort"`
+	Enabled     bool      `json:"enabled"`
+	UpdatedBy   string    `json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FeatureCohortMember 코호트에 속한 사용자 (예: beta 코호트에만 기능 공개)
+type FeatureCohortMember struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Cohort    string    `gorm:"uniqueIndex:idx_cohort_member;not null" json:"cohort"`
+	UserID    string    `gorm:"uniqueIndex:idx_cohort_member;index;not null" json:"user_id"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OAuthState 서버에서 발급한 OAuth state (CSRF 방지, 1회용)
+type OAuthState struct {
+	gorm.Model
+	State          string     `gorm:"uniqueIndex;not null"`
+	Platform       string     `gorm:"index;not null"` // 'tiktok' or 'youtube'
+	UserID         string     `gorm:"index"`          // 인증을 시작한 사용자
+	ClientID       string     // 인증을 시작한 클라이언트 (web, ios, android 등, 기록용)
+	ClientState    string     // 클라이언트가 보낸 state (콜백 시 그대로 돌려줌)
+	Scopes         string     // 기본 scope 외에 추가로 요청한 scope (공백 구분)
+	RedirectTarget string     // 콜백 후 돌아갈 앱 (oauth.redirect_targets의 이름)
+	ResponseMode   string     // 콜백 응답 방식 ("" 리다이렉트, "web_message" 팝업)
+	WebOrigin      string     // web_message로 code/state를 받을 opener origin
+	CodeVerifier   string     // PKCE code_verifier (토큰 교환 시 사용)
+	ExpiresAt      time.Time  `gorm:"index"`
+	UsedAt         *time.Time // 콜백에서 사용된 시각 (재사용 방지)
+	ExchangedAt    *time.Time // 토큰 교환에 사용된 시각
+}
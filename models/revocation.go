@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PendingRevocation 플랫폼 권한 취소에 실패해서 재시도 대기 중인 토큰
+//
+// 로컬 UserToken은 로그아웃 시 바로 삭제하고, 취소할 토큰만 여기에 남겨 둡니다.
+type PendingRevocation struct {
+	gorm.Model
+	UserID        string     `gorm:"index;not null"`
+	Platform      string     `gorm:"index;not null"`
+	AccountID     string     // 플랫폼 계정 ID (OpenID, ChannelID, IGUserID)
+	Token         string     `gorm:"not null;serializer:encrypted"` // 취소할 토큰
+	KeyID         string     `gorm:"index"`                         // 토큰 암호화에 사용된 키 ID ("" = 평문)
+	Attempts      int        // 시도 횟수
+	LastError     string     // 마지막 실패 사유
+	NextAttemptAt time.Time  `gorm:"index"`
+	GaveUpAt      *time.Time // 최대 시도 횟수 초과 (관리자 확인 필요)
+}
+
+// BeforeSave 저장 시 사용된 암호화 키 ID 기록 (UserToken.BeforeSave와 같음)
+func (p *PendingRevocation) BeforeSave(tx *gorm.DB) error {
+	if _, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+		return nil
+	}
+	tx.Statement.SetColumn("KeyID", activeKeyID())
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionRefreshToken 세션 refresh token (원문은 저장하지 않고 SHA-256 해시만 저장)
+//
+// 사용할 때마다 새 토큰으로 교체되며, 같은 로그인에서 이어진 토큰은 FamilyID가 같습니다.
+// 이미 사용된 토큰이 다시 들어오면 탈취로 보고 FamilyID 전체를 폐기합니다.
+type SessionRefreshToken struct {
+	gorm.Model
+	TokenHash string     `gorm:"uniqueIndex;not null"`
+	FamilyID  string     `gorm:"index;not null"`
+	UserID    string     `gorm:"index;not null"`
+	Platform  string     // 세션을 발급한 플랫폼 (없으면 "")
+	AccountID string     // 세션을 발급한 플랫폼 계정
+	Scope     string     // 공백 구분 권한 목록 (교체 시 그대로 유지)
+	Role      string     // 관리자 세션 권한
+	ExpiresAt time.Time  `gorm:"index"`
+	UsedAt    *time.Time // 새 토큰으로 교체된 시각
+	RevokedAt *time.Time // 로그아웃/탈취 감지로 폐기된 시각
+}
+
+// RevokedSession 로그아웃된 access JWT (jti 거부 목록, 만료 후 삭제)
+type RevokedSession struct {
+	JTI       string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"index"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
@@ -7,18 +7,55 @@ import (
 
 type UserToken struct {
     gorm.Model
-    UserID       string    `gorm:"index;not null"`  // uniqueIndex 제거 (platform과 함께 사용)
-    Platform     string    `gorm:"index;not null;default:'tiktok'"` // 'tiktok' or 'youtube'
-    AccessToken  string    `gorm:"not null"`
-    RefreshToken string    
+    UserID       string    `gorm:"index;not null;uniqueIndex:idx_user_platform_account_live,priority:1,where:deleted_at IS NULL AND account_id <> ''"`  // uniqueIndex 제거 (platform과 함께 사용)
+    Platform     string    `gorm:"index;not null;default:'tiktok';uniqueIndex:idx_user_platform_account_live,priority:2;uniqueIndex:idx_platform_account_live,priority:1,where:deleted_at IS NULL AND account_id <> ''"` // 'tiktok', 'youtube' or 'instagram'
+    AccountID    string    `gorm:"uniqueIndex:idx_user_platform_account_live,priority:3;uniqueIndex:idx_platform_account_live,priority:2"` // 플랫폼 계정 ID (사용자당 플랫폼별 여러 계정 연결 가능, 한 계정은 한 사용자에게만)
+    AccessToken  string    `gorm:"not null;serializer:encrypted"`
+    RefreshToken string    `gorm:"serializer:encrypted"`
+    KeyID        string    `gorm:"index"` // 토큰 암호화에 사용된 키 ID ("" = 평문)
     TokenType    string    
     ExpiresAt    time.Time
     Scope        string
     OpenID       string    // TikTok user open_id
     ChannelID    string    // YouTube channel ID
+    IGUserID     string    // Instagram user id
+    Status       string    `gorm:"index;not null;default:'active'"` // 'active' or 'needs_reconnect'
+    LastError    string    // 마지막 자동 갱신 실패 사유
     UpdatedAt    time.Time
 }
 
+// UserToken.Status
+const (
+    TokenStatusActive         = "active"
+    TokenStatusNeedsReconnect = "needs_reconnect" // 플랫폼이 refresh token을 거부함 (사용자 재연결 필요)
+)
+
+// BeforeSave 저장 시 사용된 암호화 키 ID 기록
+//
+// 맵으로 업데이트하면 직렬화(암호화)를 거치지 않으므로
+// AccessToken/RefreshToken은 항상 Save/Create(구조체)로 저장해야 합니다.
+func (t *UserToken) BeforeSave(tx *gorm.DB) error {
+    if _, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+        return nil
+    }
+    tx.Statement.SetColumn("KeyID", activeKeyID())
+    return nil
+}
+
+// PlatformAccountID 플랫폼 계정 ID (account_id가 비어 있는 이전 데이터는 플랫폼별 컬럼에서)
+func (t *UserToken) PlatformAccountID() string {
+    switch {
+    case t.AccountID != "":
+        return t.AccountID
+    case t.OpenID != "":
+        return t.OpenID
+    case t.ChannelID != "":
+        return t.ChannelID
+    default:
+        return t.IGUserID
+    }
+}
+
 type TikTokUser struct {
     OpenID      string `json:"open_id"`
     UnionID     string `json:"union_id"`
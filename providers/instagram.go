@@ -0,0 +1,314 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"adfit-oauth/config"
+)
+
+const (
+	instagramAuthURL    = "https://www.instagram.com/oauth/authorize"
+	instagramTokenURL   = "https://api.instagram.com/oauth/access_token"
+	instagramAPIURL     = "https://graph.instagram.com"
+	instagramAPIVersion = "v21.0"
+)
+
+// Instagram Business/Creator 계정 (Instagram API with Instagram Login)
+//
+// 단기 토큰(1시간)을 받은 즉시 장기 토큰(60일)으로 교환합니다.
+// 장기 토큰은 별도의 refresh token 없이 자기 자신으로 갱신하므로
+// UserToken.RefreshToken에도 같은 값을 저장합니다.
+type Instagram struct {
+	ClientID      string
+	ClientSecret  string
+	RedirectURI   string
+	Scopes        []string
+	AuthEndpoint  string
+	TokenEndpoint string
+	APIURL        string // Graph API 주소 (테스트 시 로컬 가짜 서버로 교체)
+	HTTPClient    *http.Client
+}
+
+func NewInstagram(cfg config.OAuthProvider) *Instagram {
+	p := &Instagram{
+		ClientID:      cfg.ClientID,
+		ClientSecret:  cfg.ClientSecret,
+		RedirectURI:   cfg.RedirectURI,
+		Scopes:        cfg.Scopes,
+		AuthEndpoint:  cfg.AuthURL,
+		TokenEndpoint: cfg.TokenURL,
+		APIURL:        strings.TrimRight(cfg.APIURL, "/"),
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if p.AuthEndpoint == "" {
+		p.AuthEndpoint = instagramAuthURL
+	}
+	if p.TokenEndpoint == "" {
+		p.TokenEndpoint = instagramTokenURL
+	}
+	if p.APIURL == "" {
+		p.APIURL = instagramAPIURL
+	}
+	if len(p.Scopes) == 0 {
+		p.Scopes = []string{"instagram_business_basic"}
+	}
+
+	return p
+}
+
+func (p *Instagram) Name() string {
+	return "instagram"
+}
+
+// Instagram Login은 PKCE를 지원하지 않으므로 code_challenge는 보내지 않음
+func (p *Instagram) AuthURL(state, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("client_id", p.ClientID)
+	params.Set("redirect_uri", p.RedirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", strings.Join(p.Scopes, ","))
+	params.Set("state", state)
+
+	return p.AuthEndpoint + "?" + params.Encode()
+}
+
+func (p *Instagram) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	// 1. 단기 토큰 발급
+	data := url.Values{}
+	data.Set("client_id", p.ClientID)
+	data.Set("client_secret", p.ClientSecret)
+	data.Set("grant_type", "authorization_code")
+	data.Set("redirect_uri", p.RedirectURI)
+	data.Set("code", code)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.TokenEndpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 응답 형식: {"data": [{...}]} 또는 {...}
+	type shortLived struct {
+		AccessToken string      `json:"access_token"`
+		UserID      json.Number `json:"user_id"`
+		Permissions interface{} `json:"permissions"`
+	}
+	var wrapped struct {
+		Data []shortLived `json:"data"`
+	}
+	var short shortLived
+	if err := json.Unmarshal(body, &wrapped); err == nil && len(wrapped.Data) > 0 {
+		short = wrapped.Data[0]
+	} else if err := json.Unmarshal(body, &short); err != nil {
+		return nil, fmt.Errorf("토큰 응답 파싱 실패: %v", err)
+	}
+	if short.AccessToken == "" {
+		return nil, &Error{Provider: "Instagram", Code: "empty_response", Description: "No access token received"}
+	}
+
+	// 2. 장기 토큰으로 교환
+	params := url.Values{}
+	params.Set("grant_type", "ig_exchange_token")
+	params.Set("client_secret", p.ClientSecret)
+	params.Set("access_token", short.AccessToken)
+
+	token, err := p.longLivedToken(ctx, "/access_token", params)
+	if err != nil {
+		return nil, err
+	}
+	token.AccountID = short.UserID.String()
+	token.Scope = permissionsString(short.Permissions)
+	return token, nil
+}
+
+func (p *Instagram) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	params := url.Values{}
+	params.Set("grant_type", "ig_refresh_token")
+	params.Set("access_token", refreshToken)
+
+	return p.longLivedToken(ctx, "/refresh_access_token", params)
+}
+
+// 장기 토큰(60일)은 만료 10일 전부터 갱신
+func (p *Instagram) RefreshWindow() time.Duration {
+	return 10 * 24 * time.Hour
+}
+
+// Instagram Login에는 권한 취소 API가 없음 (사용자가 Instagram 설정에서 직접 해제)
+func (p *Instagram) Revoke(ctx context.Context, token string) error {
+	return ErrRevokeUnsupported
+}
+
+func (p *Instagram) Profile(ctx context.Context, accessToken string) (*Profile, error) {
+	params := url.Values{}
+	params.Set("fields", "user_id,username,name,profile_picture_url,account_type,followers_count,media_count")
+	params.Set("access_token", accessToken)
+
+	var user map[string]interface{}
+	if err := p.get(ctx, "/"+instagramAPIVersion+"/me", params, &user); err != nil {
+		return nil, err
+	}
+
+	accountID := stringValue(user["user_id"])
+	if accountID == "" {
+		accountID = stringValue(user["id"])
+	}
+
+	return &Profile{
+		AccountID:   accountID,
+		DisplayName: stringValue(user["username"]),
+		AvatarURL:   stringValue(user["profile_picture_url"]),
+		Data:        user,
+	}, nil
+}
+
+// Media 미디어 목록 조회 (after: 다음 페이지 커서)
+func (p *Instagram) Media(ctx context.Context, accessToken, after string, limit int) (*MediaPage, error) {
+	params := url.Values{}
+	params.Set("fields", "id,caption,media_type,media_product_type,media_url,permalink,thumbnail_url,timestamp,like_count,comments_count")
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("access_token", accessToken)
+	if after != "" {
+		params.Set("after", after)
+	}
+
+	var result struct {
+		Data   []map[string]interface{} `json:"data"`
+		Paging struct {
+			Cursors struct {
+				After string `json:"after"`
+			} `json:"cursors"`
+			Next string `json:"next"`
+		} `json:"paging"`
+	}
+	if err := p.get(ctx, "/"+instagramAPIVersion+"/me/media", params, &result); err != nil {
+		return nil, err
+	}
+
+	page := &MediaPage{
+		Media:   result.Data,
+		Cursor:  result.Paging.Cursors.After,
+		HasMore: result.Paging.Next != "",
+	}
+	if page.Media == nil {
+		page.Media = []map[string]interface{}{}
+	}
+	return page, nil
+}
+
+// MediaPage 미디어 목록 한 페이지
+type MediaPage struct {
+	Media   []map[string]interface{} `json:"videos"`
+	Cursor  string                   `json:"cursor"`
+	HasMore bool                     `json:"has_more"`
+}
+
+// 장기 토큰 발급/갱신
+func (p *Instagram) longLivedToken(ctx context.Context, path string, params url.Values) (*Token, error) {
+	var result struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := p.get(ctx, path, params, &result); err != nil {
+		return nil, err
+	}
+	if result.AccessToken == "" {
+		return nil, &Error{Provider: "Instagram", Code: "empty_response", Description: "No long-lived token received"}
+	}
+
+	return &Token{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.AccessToken,
+		TokenType:    result.TokenType,
+		ExpiresAt:    time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (p *Instagram) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.APIURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %v", err)
+	}
+
+	body, err := p.do(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("응답 파싱 실패: %v", err)
+	}
+	return nil
+}
+
+// 요청 실행 + Graph API 에러 변환
+func (p *Instagram) do(req *http.Request) ([]byte, error) {
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Instagram 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("응답 읽기 실패: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// {"error": {"message", "type", "code"}} 또는 {"error_type", "code", "error_message"}
+		var graphErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Code    int    `json:"code"`
+			} `json:"error"`
+			ErrorType    string `json:"error_type"`
+			ErrorCode    int    `json:"code"`
+			ErrorMessage string `json:"error_message"`
+		}
+		json.Unmarshal(body, &graphErr)
+
+		code, message, graphCode := graphErr.Error.Type, graphErr.Error.Message, graphErr.Error.Code
+		if graphErr.ErrorType != "" {
+			code, message, graphCode = graphErr.ErrorType, graphErr.ErrorMessage, graphErr.ErrorCode
+		}
+		if code == "" {
+			code, message = fmt.Sprintf("http_%d", resp.StatusCode), string(body)
+		}
+		return nil, &Error{Provider: "Instagram", Code: code, Description: message, GraphCode: graphCode}
+	}
+
+	return body, nil
+}
+
+// permissions 필드 (문자열 또는 배열) → 콤마 구분 문자열
+func permissionsString(v interface{}) string {
+	switch perms := v.(type) {
+	case string:
+		return perms
+	case []interface{}:
+		var scopes []string
+		for _, perm := range perms {
+			if s, ok := perm.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return strings.Join(scopes, ",")
+	default:
+		return ""
+	}
+}
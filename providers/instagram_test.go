@@ -0,0 +1,249 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"adfit-oauth/config"
+)
+
+// 가짜 Graph API 서버와 그 주소를 쓰는 Instagram 프로바이더
+func newTestInstagram(t *testing.T, handler http.HandlerFunc) *Instagram {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return NewInstagram(config.OAuthProvider{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURI:  "https://example.com/callback",
+		TokenURL:     srv.URL + "/oauth/access_token",
+		APIURL:       srv.URL,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestInstagramExchange(t *testing.T) {
+	p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/access_token":
+			r.ParseForm()
+			if r.Method != http.MethodPost || r.PostForm.Get("code") != "auth-code" || r.PostForm.Get("grant_type") != "authorization_code" {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error_type": "OAuthException", "code": 400, "error_message": "bad code"})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{{
+					"access_token": "short-token",
+					"user_id":      17841400000000000,
+					"permissions":  "instagram_business_basic,instagram_business_manage_insights",
+				}},
+			})
+		case "/access_token":
+			q := r.URL.Query()
+			if q.Get("grant_type") != "ig_exchange_token" || q.Get("access_token") != "short-token" || q.Get("client_secret") != "client-secret" {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"message": "bad exchange", "type": "OAuthException", "code": 100}})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "long-token", "token_type": "bearer", "expires_in": 5184000})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	token, err := p.Exchange(context.Background(), "auth-code", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if token.AccessToken != "long-token" || token.RefreshToken != "long-token" {
+		t.Errorf("장기 토큰이 아님: access=%q refresh=%q", token.AccessToken, token.RefreshToken)
+	}
+	if token.AccountID != "17841400000000000" {
+		t.Errorf("AccountID = %q", token.AccountID)
+	}
+	if token.Scope != "instagram_business_basic,instagram_business_manage_insights" {
+		t.Errorf("Scope = %q", token.Scope)
+	}
+	if d := time.Until(token.ExpiresAt); d < 59*24*time.Hour || d > 61*24*time.Hour {
+		t.Errorf("만료까지 %v (60일이어야 함)", d)
+	}
+}
+
+func TestInstagramExchangeFlatResponse(t *testing.T) {
+	p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/access_token":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"access_token": "short-token",
+				"user_id":      "42",
+				"permissions":  []string{"instagram_business_basic"},
+			})
+		case "/access_token":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "long-token", "expires_in": 3600})
+		}
+	})
+
+	token, err := p.Exchange(context.Background(), "auth-code", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if token.AccountID != "42" || token.Scope != "instagram_business_basic" {
+		t.Errorf("AccountID = %q, Scope = %q", token.AccountID, token.Scope)
+	}
+}
+
+func TestInstagramRefresh(t *testing.T) {
+	p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/refresh_access_token" || q.Get("grant_type") != "ig_refresh_token" || q.Get("access_token") != "long-token" {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": map[string]interface{}{"message": "bad refresh", "type": "OAuthException", "code": 100}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "renewed-token", "token_type": "bearer", "expires_in": 5184000})
+	})
+
+	token, err := p.Refresh(context.Background(), "long-token")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if token.AccessToken != "renewed-token" || token.RefreshToken != "renewed-token" {
+		t.Errorf("access=%q refresh=%q", token.AccessToken, token.RefreshToken)
+	}
+}
+
+func TestInstagramErrorShapes(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         interface{}
+		code         string
+		graphCode    int
+		invalidGrant bool
+	}{
+		{
+			name:   "중첩 형식 만료 토큰",
+			status: http.StatusBadRequest,
+			body: map[string]interface{}{"error": map[string]interface{}{
+				"message": "Error validating access token", "type": "OAuthException", "code": 190,
+			}},
+			code: "OAuthException", graphCode: 190, invalidGrant: true,
+		},
+		{
+			name:   "중첩 형식 요청 한도",
+			status: http.StatusBadRequest,
+			body: map[string]interface{}{"error": map[string]interface{}{
+				"message": "Application request limit reached", "type": "OAuthException", "code": 4,
+			}},
+			code: "OAuthException", graphCode: 4, invalidGrant: false,
+		},
+		{
+			name:   "평면 형식 잘못된 코드",
+			status: http.StatusBadRequest,
+			body: map[string]interface{}{
+				"error_type": "OAuthException", "code": 400, "error_message": "Invalid authorization code",
+			},
+			code: "OAuthException", graphCode: 400, invalidGrant: false,
+		},
+		{
+			name:   "JSON이 아닌 응답",
+			status: http.StatusBadGateway,
+			body:   "upstream down",
+			code:   "http_502", graphCode: 0, invalidGrant: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+				if s, ok := tt.body.(string); ok {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(s))
+					return
+				}
+				writeJSON(w, tt.status, tt.body)
+			})
+
+			_, err := p.Refresh(context.Background(), "long-token")
+			pErr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("*Error가 아님: %T %v", err, err)
+			}
+			if pErr.Code != tt.code || pErr.GraphCode != tt.graphCode {
+				t.Errorf("Code = %q, GraphCode = %d", pErr.Code, pErr.GraphCode)
+			}
+			if pErr.Description == "" {
+				t.Error("Description이 비어 있음")
+			}
+			if IsInvalidGrant(err) != tt.invalidGrant {
+				t.Errorf("IsInvalidGrant = %v", !tt.invalidGrant)
+			}
+		})
+	}
+}
+
+func TestInstagramMediaPaging(t *testing.T) {
+	pages := map[string]map[string]interface{}{
+		"": {
+			"data": []map[string]interface{}{{"id": "1"}, {"id": "2"}},
+			"paging": map[string]interface{}{
+				"cursors": map[string]interface{}{"after": "cursor-1"},
+				"next":    "https://graph.instagram.com/next",
+			},
+		},
+		"cursor-1": {
+			"data":   []map[string]interface{}{{"id": "3"}},
+			"paging": map[string]interface{}{"cursors": map[string]interface{}{"after": "cursor-2"}},
+		},
+	}
+	p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if !strings.HasSuffix(r.URL.Path, "/me/media") || q.Get("access_token") != "long-token" || q.Get("limit") != "2" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, pages[q.Get("after")])
+	})
+
+	var ids []string
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		page, err := p.Media(context.Background(), "long-token", cursor, 2)
+		if err != nil {
+			t.Fatalf("Media: %v", err)
+		}
+		for _, m := range page.Media {
+			ids = append(ids, m["id"].(string))
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.Cursor
+	}
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Errorf("ids = %v", ids)
+	}
+}
+
+func TestInstagramMediaEmpty(t *testing.T) {
+	p := newTestInstagram(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+
+	page, err := p.Media(context.Background(), "long-token", "", 25)
+	if err != nil {
+		t.Fatalf("Media: %v", err)
+	}
+	if page.Media == nil || len(page.Media) != 0 || page.HasMore {
+		t.Errorf("page = %+v", page)
+	}
+}
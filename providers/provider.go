@@ -0,0 +1,158 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OAuthProvider 플랫폼별 OAuth 구현 (TikTok, YouTube ...)
+type OAuthProvider interface {
+	// Name 플랫폼 이름 (라우트의 :provider, UserToken.Platform 값)
+	Name() string
+	// AuthURL 인증 페이지 URL 생성 (PKCE S256 code_challenge 포함)
+	AuthURL(state, codeChallenge string) string
+	// Exchange authorization code → 토큰 교환
+	Exchange(ctx context.Context, code, codeVerifier string) (*Token, error)
+	// Refresh refresh token으로 토큰 갱신
+	Refresh(ctx context.Context, refreshToken string) (*Token, error)
+	// Revoke 플랫폼에 부여된 권한 취소
+	Revoke(ctx context.Context, token string) error
+	// Profile 연결된 계정 프로필 조회
+	Profile(ctx context.Context, accessToken string) (*Profile, error)
+}
+
+// Token 플랫폼에서 발급한 OAuth 토큰
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	Scope        string
+	ExpiresAt    time.Time
+	AccountID    string // 토큰 응답에 계정 ID가 포함된 경우 (TikTok open_id)
+}
+
+// Profile 연결된 계정 정보
+type Profile struct {
+	AccountID   string                 `json:"account_id"`
+	DisplayName string                 `json:"display_name"`
+	AvatarURL   string                 `json:"avatar_url"`
+	Data        map[string]interface{} `json:"data,omitempty"` // 플랫폼 원본 데이터
+}
+
+// ErrRevokeUnsupported 권한 취소 API가 없는 플랫폼
+var ErrRevokeUnsupported = errors.New("revoke not supported by provider")
+
+// Error 플랫폼 API 에러
+type Error struct {
+	Provider    string
+	Code        string
+	Description string
+	// GraphCode Meta Graph API 숫자 에러 코드 (Instagram, 없으면 0)
+	GraphCode int
+}
+
+// Graph API 에러 코드 190: access token 만료/무효 (취소, 비밀번호 변경 등 하위 코드 포함)
+const graphCodeInvalidToken = 190
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s API Error: %s - %s", e.Provider, e.Code, e.Description)
+}
+
+// IsInvalidGrant refresh token이 거부된 경우 (만료/취소 → 사용자 재연결 필요)
+//
+// Graph API의 OAuthException은 요청 제한 등 일시적인 오류에도 쓰이므로 코드 190만 거부로 봅니다.
+func IsInvalidGrant(err error) bool {
+	var pErr *Error
+	if !errors.As(err, &pErr) {
+		return false
+	}
+	return pErr.Code == "invalid_grant" || pErr.GraphCode == graphCodeInvalidToken
+}
+
+// RefreshWindower 만료 몇 시간 전부터 미리 갱신할지 지정하는 프로바이더 (선택)
+type RefreshWindower interface {
+	RefreshWindow() time.Duration
+}
+
+// ScopeUpgrader 기능별로 필요한 scope를 나중에 추가로 요청할 수 있는 프로바이더 (선택)
+type ScopeUpgrader interface {
+	// RequiredScopes 기능(user, videos ...)에 필요한 scope (모르는 기능이면 nil)
+	RequiredScopes(feature string) []string
+	// SupportedScope 추가 요청할 수 있는 scope인지
+	SupportedScope(scope string) bool
+	// AuthURLWithScopes 기본 scope에 추가 scope를 더해 인증 URL 생성
+	AuthURLWithScopes(state, codeChallenge string, scopes []string) string
+}
+
+// ParseScopes "a,b" (TikTok) 또는 "a b" (RFC 6749) 형식의 scope 목록
+func ParseScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+}
+
+// MergeScopes 기존 scope에 새로 부여된 scope 추가 (중복 제거, 공백 구분으로 저장)
+func MergeScopes(existing, granted string) string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, scope := range append(ParseScopes(existing), ParseScopes(granted)...) {
+		if !seen[scope] {
+			seen[scope] = true
+			merged = append(merged, scope)
+		}
+	}
+	return strings.Join(merged, " ")
+}
+
+// MissingScopes required 중 granted에 없는 scope
+func MissingScopes(granted string, required []string) []string {
+	have := map[string]bool{}
+	for _, scope := range ParseScopes(granted) {
+		have[scope] = true
+	}
+	var missing []string
+	for _, scope := range required {
+		if !have[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// Registry 등록된 OAuth 프로바이더 목록
+type Registry struct {
+	providers map[string]OAuthProvider
+}
+
+func NewRegistry(providers ...OAuthProvider) *Registry {
+	r := &Registry{providers: map[string]OAuthProvider{}}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register 프로바이더 등록
+func (r *Registry) Register(p OAuthProvider) {
+	r.providers[p.Name()] = p
+}
+
+// Get 이름으로 프로바이더 조회
+func (r *Registry) Get(name string) (OAuthProvider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names 등록된 프로바이더 이름 (정렬)
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,282 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"adfit-oauth/config"
+)
+
+const (
+	tiktokAuthURL  = "https://www.tiktok.com/v2/auth/authorize"
+	tiktokTokenURL = "https://open.tiktokapis.com/v2/oauth/token/"
+	tiktokAPIURL   = "https://open.tiktokapis.com"
+)
+
+// TikTok OAuth 2.0 v2 (client_key를 사용하므로 oauth2 패키지 대신 직접 구현)
+type TikTok struct {
+	ClientKey    string
+	ClientSecret string
+	RedirectURI  string
+	Scopes       []string // 처음 연결할 때 요청하는 기본 scope
+	// FeatureScopes 기능별 필요한 scope (없는 scope는 필요할 때 추가 동의를 받음)
+	FeatureScopes map[string][]string
+	AuthEndpoint  string
+	TokenEndpoint string
+	APIURL        string
+	HTTPClient    *http.Client
+}
+
+func NewTikTok(cfg config.OAuthProvider) *TikTok {
+	p := &TikTok{
+		ClientKey:    cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURI:  cfg.RedirectURI,
+		// 기본 scope만 요청하고, 확장 scope는 기능을 처음 사용할 때 추가로 요청
+		Scopes: cfg.Scopes,
+		FeatureScopes: map[string][]string{
+			"user":   {"user.info.basic"},
+			"videos": {"user.info.basic", "video.list"},
+		},
+		AuthEndpoint:  cfg.AuthURL,
+		TokenEndpoint: cfg.TokenURL,
+		APIURL:        strings.TrimRight(cfg.APIURL, "/"),
+		HTTPClient:    &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if p.AuthEndpoint == "" {
+		p.AuthEndpoint = tiktokAuthURL
+	}
+	if p.TokenEndpoint == "" {
+		p.TokenEndpoint = tiktokTokenURL
+	}
+	if p.APIURL == "" {
+		p.APIURL = tiktokAPIURL
+	}
+	if len(p.Scopes) == 0 {
+		p.Scopes = []string{"user.info.basic"}
+	}
+
+	return p
+}
+
+func (p *TikTok) Name() string {
+	return "tiktok"
+}
+
+func (p *TikTok) AuthURL(state, codeChallenge string) string {
+	return p.AuthURLWithScopes(state, codeChallenge, nil)
+}
+
+// RequiredScopes 기능별 필요한 scope
+func (p *TikTok) RequiredScopes(feature string) []string {
+	return p.FeatureScopes[feature]
+}
+
+// SupportedScope 기본 scope 또는 기능별 scope에 있는지
+func (p *TikTok) SupportedScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	for _, scopes := range p.FeatureScopes {
+		for _, s := range scopes {
+			if s == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuthURLWithScopes 기본 scope + 추가 scope (이미 동의한 scope는 TikTok이 다시 묻지 않음)
+func (p *TikTok) AuthURLWithScopes(state, codeChallenge string, scopes []string) string {
+	params := url.Values{}
+	params.Set("client_key", p.ClientKey)
+	params.Set("redirect_uri", p.RedirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", strings.Join(ParseScopes(MergeScopes(strings.Join(p.Scopes, ","), strings.Join(scopes, ","))), ","))
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	return p.AuthEndpoint + "?" + params.Encode()
+}
+
+func (p *TikTok) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	data := url.Values{}
+	data.Set("client_key", p.ClientKey)
+	data.Set("client_secret", p.ClientSecret)
+	data.Set("code", code)
+	data.Set("grant_type", "authorization_code")
+	data.Set("redirect_uri", p.RedirectURI)
+	data.Set("code_verifier", codeVerifier)
+
+	return p.requestToken(ctx, data)
+}
+
+func (p *TikTok) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	data := url.Values{}
+	data.Set("client_key", p.ClientKey)
+	data.Set("client_secret", p.ClientSecret)
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+
+	return p.requestToken(ctx, data)
+}
+
+func (p *TikTok) Revoke(ctx context.Context, token string) error {
+	data := url.Values{}
+	data.Set("client_key", p.ClientKey)
+	data.Set("client_secret", p.ClientSecret)
+	data.Set("token", token)
+
+	body, status, err := p.postForm(ctx, p.APIURL+"/v2/oauth/revoke/", data)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	json.Unmarshal(body, &result)
+	if result.Error != "" {
+		return &Error{Provider: "TikTok", Code: result.Error, Description: result.ErrorDescription}
+	}
+	if status != http.StatusOK {
+		return &Error{Provider: "TikTok", Code: fmt.Sprintf("http_%d", status), Description: string(body)}
+	}
+	return nil
+}
+
+func (p *TikTok) Profile(ctx context.Context, accessToken string) (*Profile, error) {
+	// user.info.basic scope에서 사용 가능한 필드만 요청
+	fields := "open_id,union_id,avatar_url,display_name"
+	apiURL := fmt.Sprintf("%s/v2/user/info/?fields=%s", p.APIURL, url.QueryEscape(fields))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("사용자 정보 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("응답 읽기 실패: %v", err)
+	}
+
+	var result struct {
+		Data struct {
+			User map[string]interface{} `json:"user"`
+		} `json:"data"`
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("응답 파싱 실패: %v", err)
+	}
+
+	// TikTok API는 error.code가 "ok"일 때도 에러 객체를 반환함
+	if result.Error.Code != "" && result.Error.Code != "ok" {
+		return nil, &Error{Provider: "TikTok", Code: result.Error.Code, Description: result.Error.Message}
+	}
+
+	user := result.Data.User
+	if user == nil {
+		return nil, &Error{Provider: "TikTok", Code: "empty_response", Description: "No user data in response"}
+	}
+
+	return &Profile{
+		AccountID:   stringValue(user["open_id"]),
+		DisplayName: stringValue(user["display_name"]),
+		AvatarURL:   stringValue(user["avatar_url"]),
+		Data:        user,
+	}, nil
+}
+
+// 토큰 엔드포인트 호출
+func (p *TikTok) requestToken(ctx context.Context, data url.Values) (*Token, error) {
+	body, _, err := p.postForm(ctx, p.TokenEndpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		OpenID           string `json:"open_id"`
+		RefreshToken     string `json:"refresh_token"`
+		Scope            string `json:"scope"`
+		TokenType        string `json:"token_type"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("토큰 응답 파싱 실패: %v", err)
+	}
+
+	if tokenResp.Error != "" {
+		return nil, &Error{Provider: "TikTok", Code: tokenResp.Error, Description: tokenResp.ErrorDescription}
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, &Error{Provider: "TikTok", Code: "empty_response", Description: "No access token received"}
+	}
+
+	return &Token{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		Scope:        tokenResp.Scope,
+		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+		AccountID:    tokenResp.OpenID,
+	}, nil
+}
+
+func (p *TikTok) postForm(ctx context.Context, endpoint string, data url.Values) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, 0, fmt.Errorf("요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("TikTok 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("응답 읽기 실패: %v", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+func stringValue(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
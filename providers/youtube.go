@@ -0,0 +1,189 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+
+	"adfit-oauth/config"
+)
+
+const googleRevokeURL = "https://oauth2.googleapis.com/revoke"
+
+// YouTube Google OAuth 2.0 (oauth2 패키지 사용)
+type YouTube struct {
+	config *oauth2.Config
+}
+
+func NewYouTube(cfg config.OAuthProvider) *YouTube {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		fmt.Println("⚠️ WARNING: oauth.youtube.client_id/client_secret not set")
+	}
+
+	endpoint := google.Endpoint
+	if cfg.AuthURL != "" {
+		endpoint.AuthURL = cfg.AuthURL
+	}
+	if cfg.TokenURL != "" {
+		endpoint.TokenURL = cfg.TokenURL
+	}
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{
+			"https://www.googleapis.com/auth/youtube.readonly",
+			"https://www.googleapis.com/auth/yt-analytics.readonly",
+			"https://www.googleapis.com/auth/userinfo.profile",
+			"https://www.googleapis.com/auth/userinfo.email",
+		}
+	}
+
+	return &YouTube{
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     endpoint,
+			RedirectURL:  cfg.RedirectURI,
+			Scopes:       scopes,
+		},
+	}
+}
+
+// OAuth2Config YouTube Data/Analytics API 클라이언트 생성용
+func (p *YouTube) OAuth2Config() *oauth2.Config {
+	return p.config
+}
+
+func (p *YouTube) Name() string {
+	return "youtube"
+}
+
+func (p *YouTube) AuthURL(state, codeChallenge string) string {
+	return p.config.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+func (p *YouTube) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, googleError(err)
+	}
+	return fromOAuth2Token(token), nil
+}
+
+func (p *YouTube) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	// 만료된 토큰으로 TokenSource를 만들면 refresh token으로 갱신
+	expired := &oauth2.Token{
+		RefreshToken: refreshToken,
+		Expiry:       time.Now().Add(-time.Minute),
+	}
+	token, err := p.config.TokenSource(ctx, expired).Token()
+	if err != nil {
+		return nil, googleError(err)
+	}
+	result := fromOAuth2Token(token)
+	if result.RefreshToken == "" {
+		result.RefreshToken = refreshToken
+	}
+	return result, nil
+}
+
+func (p *YouTube) Revoke(ctx context.Context, token string) error {
+	data := url.Values{}
+	data.Set("token", token)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", googleRevokeURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Google revoke 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &Error{Provider: "Google", Code: fmt.Sprintf("http_%d", resp.StatusCode), Description: string(body)}
+	}
+	return nil
+}
+
+func (p *YouTube) Profile(ctx context.Context, accessToken string) (*Profile, error) {
+	client := p.config.Client(ctx, &oauth2.Token{AccessToken: accessToken, TokenType: "Bearer"})
+	youtubeService, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("YouTube 서비스 생성 실패: %v", err)
+	}
+
+	// 채널 정보 가져오기
+	channelsResponse, err := youtubeService.Channels.List([]string{"snippet", "statistics"}).Mine(true).Do()
+	if err != nil {
+		return nil, fmt.Errorf("채널 정보 조회 실패: %v", err)
+	}
+	if len(channelsResponse.Items) == 0 {
+		return nil, &Error{Provider: "YouTube", Code: "no_channel", Description: "No channel found"}
+	}
+
+	channel := channelsResponse.Items[0]
+	return &Profile{
+		AccountID:   channel.Id,
+		DisplayName: channel.Snippet.Title,
+		AvatarURL:   channel.Snippet.Thumbnails.Default.Url,
+		Data:        ChannelInfo(channel),
+	}, nil
+}
+
+// ChannelInfo Flutter 앱에서 사용하는 채널 정보 형식
+func ChannelInfo(channel *youtube.Channel) map[string]interface{} {
+	return map[string]interface{}{
+		"id": channel.Id,
+		"snippet": map[string]interface{}{
+			"title":       channel.Snippet.Title,
+			"description": channel.Snippet.Description,
+			"thumbnails": map[string]interface{}{
+				"default": map[string]interface{}{
+					"url": channel.Snippet.Thumbnails.Default.Url,
+				},
+			},
+		},
+		"statistics": map[string]interface{}{
+			"subscriberCount": channel.Statistics.SubscriberCount,
+			"videoCount":      channel.Statistics.VideoCount,
+			"viewCount":       channel.Statistics.ViewCount,
+		},
+		"connected": true,
+	}
+}
+
+func fromOAuth2Token(token *oauth2.Token) *Token {
+	scope, _ := token.Extra("scope").(string)
+	return &Token{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		TokenType:    token.TokenType,
+		Scope:        scope,
+		ExpiresAt:    token.Expiry,
+	}
+}
+
+// oauth2.RetrieveError → providers.Error
+func googleError(err error) error {
+	if rErr, ok := err.(*oauth2.RetrieveError); ok && rErr.ErrorCode != "" {
+		return &Error{Provider: "Google", Code: rErr.ErrorCode, Description: rErr.ErrorDescription}
+	}
+	return err
+}
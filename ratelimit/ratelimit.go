@@ -0,0 +1,217 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// 예산 이름
+const (
+	BudgetDefault = "default" // 인증된 API (사용자/API 키별)
+	BudgetOAuth   = "oauth"   // 공개 OAuth/로그인 라우트 (IP별)
+	BudgetStats   = "stats"   // 통계 업데이트/수동 실행
+)
+
+// Limit 토큰 버킷 예산 (분당 보충량 + 최대 버스트)
+type Limit struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Enabled 0 이하면 제한 없음
+func (l Limit) Enabled() bool {
+	return l.RequestsPerMinute > 0
+}
+
+// Capacity 버킷 크기 (burst가 없으면 분당 요청 수)
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.RequestsPerMinute
+}
+
+// 초당 보충 토큰 수
+func (l Limit) rate() float64 {
+	return float64(l.RequestsPerMinute) / 60
+}
+
+// Result 요청 허용 여부 + 응답 헤더 값
+type Result struct {
+	Allowed    bool
+	Limit      int           // RateLimit-Limit (버킷 크기)
+	Remaining  int           // RateLimit-Remaining
+	Reset      time.Duration // RateLimit-Reset (버킷이 다시 가득 찰 때까지)
+	RetryAfter time.Duration // 거부된 경우 다음 토큰까지
+}
+
+// Store 버킷 저장소 (여러 인스턴스에서는 Redis 등 공유 저장소 구현을 등록)
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter 예산별 요청 제한 (예산은 설정 재적용 시 교체 가능)
+type Limiter struct {
+	store Store
+
+	mu      sync.RWMutex
+	budgets map[string]Limit
+}
+
+func NewLimiter(store Store, budgets map[string]Limit) *Limiter {
+	l := &Limiter{store: store}
+	l.SetBudgets(budgets)
+	return l
+}
+
+// SetBudgets 예산 교체 (기존 버킷은 새 예산으로 계속 보충)
+func (l *Limiter) SetBudgets(budgets map[string]Limit) {
+	copied := make(map[string]Limit, len(budgets))
+	for name, limit := range budgets {
+		copied[name] = limit
+	}
+	l.mu.Lock()
+	l.budgets = copied
+	l.mu.Unlock()
+}
+
+// Budget 예산 조회 (없거나 0이면 제한 없음)
+func (l *Limiter) Budget(name string) (Limit, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	limit, ok := l.budgets[name]
+	return limit, ok && limit.Enabled()
+}
+
+// Allow 예산에서 토큰 하나 사용 (identity: user:..., apikey:..., ip:...)
+func (l *Limiter) Allow(ctx context.Context, budget, identity string) (Result, bool, error) {
+	limit, ok := l.Budget(budget)
+	if !ok {
+		return Result{Allowed: true}, false, nil
+	}
+	result, err := l.store.Take(ctx, budget+"|"+identity, limit)
+	return result, true, err
+}
+
+// 백엔드 등록 (이름 → 생성 함수)
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]func() (Store, error){
+		"memory": func() (Store, error) { return NewMemoryStore(), nil },
+	}
+)
+
+// RegisterBackend 버킷 저장소 구현 등록 (rate_limit.backend에서 이름으로 선택)
+func RegisterBackend(name string, factory func() (Store, error)) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = factory
+}
+
+// NewStore 등록된 백엔드로 저장소 생성 (비어 있으면 memory)
+func NewStore(backend string) (Store, error) {
+	if backend == "" {
+		backend = "memory"
+	}
+	backendsMu.RLock()
+	factory, ok := backends[backend]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown rate limit backend: %s", backend)
+	}
+	return factory()
+}
+
+// MemoryStore 단일 인스턴스용 메모리 토큰 버킷
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time // 이 시각 이후에는 가득 찬 버킷과 같으므로 삭제 가능
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// 오래된 버킷 정리 주기
+const sweepInterval = time.Minute
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Capacity())
+	rate := limit.rate()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+		b.updated = now
+	}
+
+	result := Result{Limit: limit.Capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsToDuration((capacity - b.tokens) / rate)
+	b.fullAt = now.Add(result.Reset)
+	return result, nil
+}
+
+// 가득 찬 버킷 삭제 (다음 요청 때 가득 찬 상태로 다시 생성)
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(math.Ceil(sec * float64(time.Second)))
+}
+
+// 전역 Limiter (미들웨어에서 사용)
+var (
+	mu      sync.RWMutex
+	current *Limiter
+)
+
+// SetDefault 전역 Limiter 설정
+func SetDefault(l *Limiter) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = l
+}
+
+// Default 전역 Limiter (설정되지 않았으면 nil → 요청 제한 비활성화)
+func Default() *Limiter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// 시각을 직접 움직이는 MemoryStore
+func newTestStore() (*MemoryStore, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	s, now := newTestStore()
+	limit := Limit{RequestsPerMinute: 60, Burst: 3} // 초당 1개 보충
+	ctx := context.Background()
+
+	// 버스트만큼 허용
+	for i := 2; i >= 0; i-- {
+		result, _ := s.Take(ctx, "k", limit)
+		if !result.Allowed || result.Remaining != i || result.Limit != 3 {
+			t.Fatalf("버스트 요청: %+v", result)
+		}
+	}
+
+	// 버킷이 비면 거부 + 다음 토큰까지 대기 시간
+	result, _ := s.Take(ctx, "k", limit)
+	if result.Allowed {
+		t.Fatal("버스트를 넘은 요청이 허용됨")
+	}
+	if result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("RetryAfter = %v, Reset = %v", result.RetryAfter, result.Reset)
+	}
+
+	// 다른 키는 별도 버킷
+	if result, _ := s.Take(ctx, "other", limit); !result.Allowed {
+		t.Error("다른 키가 거부됨")
+	}
+
+	// 1초 뒤 토큰 하나 보충
+	*now = now.Add(time.Second)
+	if result, _ := s.Take(ctx, "k", limit); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("보충 후: %+v", result)
+	}
+
+	// 오래 지나도 버스트 이상으로 쌓이지 않음
+	*now = now.Add(time.Hour)
+	if result, _ := s.Take(ctx, "k", limit); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("가득 찬 버킷: %+v", result)
+	}
+}
+
+func TestMemoryStoreCapacityWithoutBurst(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{RequestsPerMinute: 2}
+
+	for i := 0; i < 2; i++ {
+		if result, _ := s.Take(context.Background(), "k", limit); !result.Allowed {
+			t.Fatalf("%d번째 요청이 거부됨", i+1)
+		}
+	}
+	result, _ := s.Take(context.Background(), "k", limit)
+	if result.Allowed || result.RetryAfter != 30*time.Second {
+		t.Errorf("burst 없으면 분당 요청 수가 버킷 크기: %+v", result)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, now := newTestStore()
+	limit := Limit{RequestsPerMinute: 60, Burst: 3}
+
+	s.Take(context.Background(), "k", limit)
+	*now = now.Add(2 * sweepInterval)
+	s.Take(context.Background(), "other", limit)
+
+	if _, ok := s.buckets["k"]; ok {
+		t.Error("가득 찬 버킷이 정리되지 않음")
+	}
+}
+
+func TestLimiterBudgets(t *testing.T) {
+	l := NewLimiter(NewMemoryStore(), map[string]Limit{
+		BudgetOAuth:   {RequestsPerMinute: 60, Burst: 1},
+		BudgetDefault: {RequestsPerMinute: 0},
+	})
+	ctx := context.Background()
+
+	// 0이거나 없는 예산은 제한 없음
+	for _, budget := range []string{BudgetDefault, BudgetStats} {
+		if result, limited, _ := l.Allow(ctx, budget, "ip:1"); limited || !result.Allowed {
+			t.Errorf("%s: limited=%v allowed=%v", budget, limited, result.Allowed)
+		}
+	}
+
+	if result, limited, _ := l.Allow(ctx, BudgetOAuth, "ip:1"); !limited || !result.Allowed {
+		t.Fatalf("첫 요청: limited=%v allowed=%v", limited, result.Allowed)
+	}
+	if result, _, _ := l.Allow(ctx, BudgetOAuth, "ip:1"); result.Allowed {
+		t.Error("버스트를 넘은 요청이 허용됨")
+	}
+	// 예산별, 식별자별로 버킷이 다름
+	if result, _, _ := l.Allow(ctx, BudgetOAuth, "ip:2"); !result.Allowed {
+		t.Error("다른 식별자가 거부됨")
+	}
+
+	// 예산 교체 후 제한 해제
+	l.SetBudgets(map[string]Limit{})
+	if _, limited, _ := l.Allow(ctx, BudgetOAuth, "ip:1"); limited {
+		t.Error("예산 제거 후에도 제한됨")
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	if _, err := NewStore(""); err != nil {
+		t.Errorf("기본 백엔드: %v", err)
+	}
+	if _, err := NewStore("redis"); err == nil {
+		t.Error("등록되지 않은 백엔드는 에러여야 함")
+	}
+}
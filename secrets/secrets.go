@@ -0,0 +1,135 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 설정 값에 쓸 수 있는 비밀 값 참조
+//
+//	env:NAME                 환경변수 NAME
+//	file:/run/secrets/name   파일 내용 (끝의 줄바꿈 제거)
+//	sm://name                시크릿 매니저 (기본은 로컬 파일 대체 구현, RegisterResolver("sm", ...)로 교체)
+//
+// 등록된 scheme으로 시작하지 않는 값은 그대로 사용합니다.
+
+// Resolver scheme 뒤의 참조를 실제 값으로 변환
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc 함수를 Resolver로 사용
+type ResolverFunc func(ref string) (string, error)
+
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// scheme → Resolver
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{
+		"env":  ResolverFunc(resolveEnv),
+		"file": ResolverFunc(resolveFile),
+		"sm":   NewFileManager(""),
+	}
+)
+
+// RegisterResolver scheme별 Resolver 등록 (같은 scheme은 교체, 예: 실제 시크릿 매니저 클라이언트)
+func RegisterResolver(scheme string, r Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[scheme] = r
+}
+
+// IsReference 등록된 scheme의 참조인지
+func IsReference(value string) bool {
+	_, _, ok := lookup(value)
+	return ok
+}
+
+// Resolve 참조면 실제 값, 아니면 value 그대로
+func Resolve(value string) (string, error) {
+	r, ref, ok := lookup(value)
+	if !ok {
+		return value, nil
+	}
+	resolved, err := r.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("비밀 값 %q 조회 실패: %v", value, err)
+	}
+	return resolved, nil
+}
+
+// "scheme:ref" 또는 "scheme://ref" 분리
+func lookup(value string) (Resolver, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok || scheme == "" {
+		return nil, "", false
+	}
+	resolversMu.RLock()
+	r, ok := resolvers[scheme]
+	resolversMu.RUnlock()
+	if !ok {
+		return nil, "", false
+	}
+	return r, strings.TrimPrefix(ref, "//"), true
+}
+
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("환경변수 %s가 설정되지 않았습니다", name)
+	}
+	return value, nil
+}
+
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// FileManager 로컬 개발/테스트용 시크릿 매니저 대체 구현 (YAML 파일의 이름 → 값)
+//
+//	tiktok-client-secret: "..."
+//	jwt-secret: "..."
+type FileManager struct {
+	Path string
+}
+
+// NewFileManager path가 비어 있으면 환경변수 SECRETS_FILE, 없으면 config/secrets.local.yaml
+func NewFileManager(path string) *FileManager {
+	return &FileManager{Path: path}
+}
+
+// Resolve 호출할 때마다 파일을 다시 읽음 (설정 재로드 시 새 값 반영)
+func (m *FileManager) Resolve(name string) (string, error) {
+	path := m.Path
+	if path == "" {
+		path = os.Getenv("SECRETS_FILE")
+	}
+	if path == "" {
+		path = "config/secrets.local.yaml"
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("시크릿 파일 읽기 실패: %v", err)
+	}
+	var values map[string]string
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("시크릿 파일 파싱 실패 (%s): %v", path, err)
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", fmt.Errorf("%s에 %q가 없습니다", path, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("파일 쓰기 실패: %v", err)
+	}
+	return path
+}
+
+func TestFileManager(t *testing.T) {
+	path := writeFile(t, "secrets.yaml", "tiktok-client-secret: \"tk-secret\"\njwt-secret: jwt-value\n")
+	m := NewFileManager(path)
+
+	value, err := m.Resolve("tiktok-client-secret")
+	if err != nil || value != "tk-secret" {
+		t.Errorf("Resolve = %q, %v", value, err)
+	}
+	if _, err := m.Resolve("missing"); err == nil {
+		t.Error("없는 이름은 에러여야 함")
+	}
+
+	// 호출할 때마다 다시 읽으므로 파일 변경이 바로 반영됨
+	if err := os.WriteFile(path, []byte("jwt-secret: rotated\n"), 0600); err != nil {
+		t.Fatalf("파일 쓰기 실패: %v", err)
+	}
+	if value, err := m.Resolve("jwt-secret"); err != nil || value != "rotated" {
+		t.Errorf("변경 후 Resolve = %q, %v", value, err)
+	}
+}
+
+func TestFileManagerDefaultPath(t *testing.T) {
+	path := writeFile(t, "secrets.yaml", "name: from-env-path\n")
+	t.Setenv("SECRETS_FILE", path)
+
+	value, err := NewFileManager("").Resolve("name")
+	if err != nil || value != "from-env-path" {
+		t.Errorf("Resolve = %q, %v", value, err)
+	}
+}
+
+func TestFileManagerErrors(t *testing.T) {
+	if _, err := NewFileManager(filepath.Join(t.TempDir(), "none.yaml")).Resolve("name"); err == nil {
+		t.Error("없는 파일은 에러여야 함")
+	}
+	if _, err := NewFileManager(writeFile(t, "bad.yaml", "- not\n- a map\n")).Resolve("name"); err == nil {
+		t.Error("맵이 아닌 파일은 에러여야 함")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("ADFIT_TEST_SECRET", "env-value")
+	filePath := writeFile(t, "secret.txt", "file-value\n")
+	smPath := writeFile(t, "sm.yaml", "db-password: sm-value\n")
+	t.Setenv("SECRETS_FILE", smPath)
+
+	tests := map[string]string{
+		"env:ADFIT_TEST_SECRET": "env-value",
+		"file:" + filePath:      "file-value",
+		"sm://db-password":      "sm-value",
+		"plain-value":           "plain-value",
+		"https://example.com":   "https://example.com", // 등록되지 않은 scheme은 그대로
+	}
+	for value, want := range tests {
+		got, err := Resolve(value)
+		if err != nil || got != want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+
+	if _, err := Resolve("env:ADFIT_TEST_SECRET_MISSING"); err == nil {
+		t.Error("설정되지 않은 환경변수는 에러여야 함")
+	}
+}
+
+func TestRegisterResolver(t *testing.T) {
+	prev := resolvers["sm"]
+	t.Cleanup(func() { RegisterResolver("sm", prev) })
+
+	RegisterResolver("sm", ResolverFunc(func(ref string) (string, error) {
+		return "managed:" + ref, nil
+	}))
+	if got, err := Resolve("sm://api-key"); err != nil || got != "managed:api-key" {
+		t.Errorf("Resolve = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+)
+
+// 관리자 비밀번호 최소 길이
+const adminMinPasswordLength = 12
+
+var (
+	ErrAdminInvalidCredentials = errors.New("invalid username or password")
+	ErrAdminInvalidRole        = errors.New("invalid admin role")
+	ErrAdminWeakPassword       = fmt.Errorf("password must be at least %d characters", adminMinPasswordLength)
+)
+
+// 존재하지 않는 계정도 같은 시간이 걸리도록 비교할 더미 해시
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("adfit-dummy-password"), bcrypt.DefaultCost)
+
+// AdminAccountService SQLite 관리자 계정
+type AdminAccountService struct {
+	DB *gorm.DB
+}
+
+func NewAdminAccountService(db *gorm.DB) *AdminAccountService {
+	return &AdminAccountService{DB: db}
+}
+
+// IsValidAdminRole 관리자 권한 이름 확인
+func IsValidAdminRole(role string) bool {
+	for _, r := range models.AdminRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Create 관리자 계정 생성
+func (s *AdminAccountService) Create(username, password, role string) (*models.AdminAccount, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	if !IsValidAdminRole(role) {
+		return nil, ErrAdminInvalidRole
+	}
+	hash, err := hashAdminPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	account := models.AdminAccount{Username: username, PasswordHash: hash, Role: role}
+	if err := s.DB.Create(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Authenticate 아이디/비밀번호 확인 (비활성 계정 거부)
+func (s *AdminAccountService) Authenticate(username, password string) (*models.AdminAccount, error) {
+	var account models.AdminAccount
+	err := s.DB.Where("username = ?", strings.TrimSpace(username)).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrAdminInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrAdminInvalidCredentials
+	}
+	if account.Disabled {
+		return nil, ErrAdminInvalidCredentials
+	}
+
+	now := time.Now()
+	account.LastLoginAt = &now
+	s.DB.Model(&account).Update("last_login_at", now)
+	return &account, nil
+}
+
+// List 관리자 계정 목록
+func (s *AdminAccountService) List() ([]models.AdminAccount, error) {
+	var accounts []models.AdminAccount
+	err := s.DB.Order("username").Find(&accounts).Error
+	return accounts, err
+}
+
+// AdminAccountUpdate 변경할 항목 (nil이면 유지)
+type AdminAccountUpdate struct {
+	Role     *string
+	Disabled *bool
+	Password *string
+}
+
+// Update 권한/비활성화/비밀번호 변경
+func (s *AdminAccountService) Update(id uint, update AdminAccountUpdate) (*models.AdminAccount, error) {
+	var account models.AdminAccount
+	if err := s.DB.First(&account, id).Error; err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+	if update.Role != nil {
+		if !IsValidAdminRole(*update.Role) {
+			return nil, ErrAdminInvalidRole
+		}
+		changes["role"] = *update.Role
+	}
+	if update.Disabled != nil {
+		changes["disabled"] = *update.Disabled
+	}
+	if update.Password != nil {
+		hash, err := hashAdminPassword(*update.Password)
+		if err != nil {
+			return nil, err
+		}
+		changes["password_hash"] = hash
+	}
+	if len(changes) == 0 {
+		return &account, nil
+	}
+
+	if err := s.DB.Model(&account).Updates(changes).Error; err != nil {
+		return nil, err
+	}
+	return &account, s.DB.First(&account, id).Error
+}
+
+// AdminSessionUserID 관리자 세션의 user_id (앱 사용자 ID와 겹치지 않도록 접두어 사용)
+func AdminSessionUserID(username string) string {
+	return "admin:" + username
+}
+
+func hashAdminPassword(password string) (string, error) {
+	if len(password) < adminMinPasswordLength {
+		return "", ErrAdminWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
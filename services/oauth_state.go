@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+)
+
+// OAuth state 유효시간
+const OAuthStateTTL = 10 * time.Minute
+
+var (
+	ErrStateNotFound = errors.New("알 수 없는 state")
+	ErrStateUsed     = errors.New("이미 사용된 state")
+	ErrStateExpired  = errors.New("만료된 state")
+	ErrStateNotReady = errors.New("콜백을 거치지 않은 state")
+	ErrStateNotOwned = errors.New("다른 사용자에게 발급된 state")
+)
+
+// OAuthStateStore SQLite에 OAuth state를 저장/검증
+type OAuthStateStore struct {
+	DB *gorm.DB
+}
+
+func NewOAuthStateStore(db *gorm.DB) *OAuthStateStore {
+	return &OAuthStateStore{DB: db}
+}
+
+// StateOptions state에 바인딩할 요청 정보
+type StateOptions struct {
+	UserID      string   // 인증을 시작한 사용자 (토큰 교환 시 같은 사용자만 허용)
+	ClientID    string   // web, ios, android 등 (기록용, 검증하지 않음)
+	ClientState string   // 콜백 시 그대로 돌려줄 클라이언트 state
+	Scopes      []string // 추가로 요청한 scope
+	// RedirectTarget 콜백 후 돌아갈 앱 (oauth.redirect_targets의 이름)
+	RedirectTarget string
+	// ResponseMode "web_message"면 콜백에서 WebOrigin의 opener 창으로 postMessage
+	ResponseMode string
+	WebOrigin    string
+}
+
+// Issue 새 state 발급
+func (s *OAuthStateStore) Issue(platform string, opts StateOptions) (*models.OAuthState, error) {
+	value, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("state 생성 실패: %v", err)
+	}
+
+	// 만료된 state 정리 (하루 이상 지난 것)
+	s.DB.Unscoped().Where("expires_at < ?", time.Now().Add(-24*time.Hour)).Delete(&models.OAuthState{})
+
+	state := &models.OAuthState{
+		State:          value,
+		Platform:       platform,
+		UserID:         opts.UserID,
+		ClientID:       opts.ClientID,
+		ClientState:    opts.ClientState,
+		Scopes:         strings.Join(opts.Scopes, " "),
+		RedirectTarget: opts.RedirectTarget,
+		ResponseMode:   opts.ResponseMode,
+		WebOrigin:      opts.WebOrigin,
+		CodeVerifier:   oauth2.GenerateVerifier(),
+		ExpiresAt:      time.Now().Add(OAuthStateTTL),
+	}
+	if err := s.DB.Create(state).Error; err != nil {
+		return nil, fmt.Errorf("state 저장 실패: %v", err)
+	}
+
+	return state, nil
+}
+
+// Consume state 검증 후 사용 처리 (1회용)
+func (s *OAuthStateStore) Consume(platform, value string) (*models.OAuthState, error) {
+	if value == "" {
+		return nil, ErrStateNotFound
+	}
+
+	now := time.Now()
+	result := s.DB.Model(&models.OAuthState{}).
+		Where("state = ? AND platform = ? AND used_at IS NULL AND expires_at > ?", value, platform, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("state 업데이트 실패: %v", result.Error)
+	}
+
+	var state models.OAuthState
+	if err := s.DB.Where("state = ? AND platform = ?", value, platform).First(&state).Error; err != nil {
+		return nil, ErrStateNotFound
+	}
+
+	// 동시 요청 중 하나만 성공
+	if result.RowsAffected == 0 {
+		if state.UsedAt != nil {
+			return nil, ErrStateUsed
+		}
+		return nil, ErrStateExpired
+	}
+
+	return &state, nil
+}
+
+// ClaimForExchange 콜백을 거친 state를 토큰 교환용으로 1회 사용 처리
+// (PKCE code_verifier를 돌려받기 위해 사용)
+//
+// state를 발급받은 사용자(userID)만 사용할 수 있으며, 다른 사용자의 요청은 state를 소모하지 않습니다.
+func (s *OAuthStateStore) ClaimForExchange(platform, value, userID string) (*models.OAuthState, error) {
+	if value == "" {
+		return nil, ErrStateNotFound
+	}
+
+	now := time.Now()
+	result := s.DB.Model(&models.OAuthState{}).
+		Where("state = ? AND platform = ? AND user_id = ? AND user_id <> '' AND used_at IS NOT NULL AND exchanged_at IS NULL AND expires_at > ?", value, platform, userID, now).
+		Update("exchanged_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("state 업데이트 실패: %v", result.Error)
+	}
+
+	var state models.OAuthState
+	if err := s.DB.Where("state = ? AND platform = ?", value, platform).First(&state).Error; err != nil {
+		return nil, ErrStateNotFound
+	}
+
+	if result.RowsAffected == 0 {
+		switch {
+		case state.UserID == "" || state.UserID != userID:
+			return nil, ErrStateNotOwned
+		case state.UsedAt == nil:
+			return nil, ErrStateNotReady
+		case state.ExchangedAt != nil:
+			return nil, ErrStateUsed
+		default:
+			return nil, ErrStateExpired
+		}
+	}
+
+	return &state, nil
+}
+
+// CodeChallenge PKCE S256 code_challenge 계산
+func CodeChallenge(state *models.OAuthState) string {
+	return oauth2.S256ChallengeFromVerifier(state.CodeVerifier)
+}
+
+// URL-safe 랜덤 문자열 생성
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"adfit-oauth/models"
+)
+
+// 메모리 DB를 쓰는 OAuthStateStore (연결 하나만 사용해야 같은 메모리 DB를 봄)
+func newTestStateStore(t *testing.T) *OAuthStateStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("DB 열기 실패: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.OAuthState{}); err != nil {
+		t.Fatalf("마이그레이션 실패: %v", err)
+	}
+	return NewOAuthStateStore(db)
+}
+
+func TestOAuthStateFlow(t *testing.T) {
+	s := newTestStateStore(t)
+
+	issued, err := s.Issue("tiktok", StateOptions{UserID: "user-1", Scopes: []string{"video.list", "user.info.stats"}})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if issued.State == "" || issued.CodeVerifier == "" || issued.Scopes != "video.list user.info.stats" {
+		t.Errorf("발급된 state = %+v", issued)
+	}
+
+	// 콜백 전에는 토큰 교환 불가
+	if _, err := s.ClaimForExchange("tiktok", issued.State, "user-1"); !errors.Is(err, ErrStateNotReady) {
+		t.Errorf("콜백 전 ClaimForExchange err = %v, want ErrStateNotReady", err)
+	}
+
+	// 다른 플랫폼의 콜백으로는 사용할 수 없음
+	if _, err := s.Consume("youtube", issued.State); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("다른 플랫폼 Consume err = %v, want ErrStateNotFound", err)
+	}
+
+	consumed, err := s.Consume("tiktok", issued.State)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if consumed.UserID != "user-1" || consumed.UsedAt == nil {
+		t.Errorf("Consume 결과 = %+v", consumed)
+	}
+	if _, err := s.Consume("tiktok", issued.State); !errors.Is(err, ErrStateUsed) {
+		t.Errorf("콜백 재사용 err = %v, want ErrStateUsed", err)
+	}
+
+	// 다른 사용자는 state를 소모하지 못함 (발급받은 사용자의 교환은 그대로 가능)
+	if _, err := s.ClaimForExchange("tiktok", issued.State, "user-2"); !errors.Is(err, ErrStateNotOwned) {
+		t.Errorf("다른 사용자 ClaimForExchange err = %v, want ErrStateNotOwned", err)
+	}
+
+	claimed, err := s.ClaimForExchange("tiktok", issued.State, "user-1")
+	if err != nil {
+		t.Fatalf("ClaimForExchange: %v", err)
+	}
+	if claimed.CodeVerifier != issued.CodeVerifier || CodeChallenge(claimed) == "" {
+		t.Error("PKCE code_verifier가 유지되지 않음")
+	}
+	if _, err := s.ClaimForExchange("tiktok", issued.State, "user-1"); !errors.Is(err, ErrStateUsed) {
+		t.Errorf("토큰 교환 재사용 err = %v, want ErrStateUsed", err)
+	}
+}
+
+func TestOAuthStateExpired(t *testing.T) {
+	s := newTestStateStore(t)
+	expire := func(state *models.OAuthState) {
+		t.Helper()
+		if err := s.DB.Model(state).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+			t.Fatalf("만료 처리 실패: %v", err)
+		}
+	}
+
+	issued, _ := s.Issue("tiktok", StateOptions{UserID: "user-1"})
+	expire(issued)
+	if _, err := s.Consume("tiktok", issued.State); !errors.Is(err, ErrStateExpired) {
+		t.Errorf("만료된 state Consume err = %v, want ErrStateExpired", err)
+	}
+
+	// 콜백 후 교환 전에 만료
+	issued, _ = s.Issue("tiktok", StateOptions{UserID: "user-1"})
+	if _, err := s.Consume("tiktok", issued.State); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	expire(issued)
+	if _, err := s.ClaimForExchange("tiktok", issued.State, "user-1"); !errors.Is(err, ErrStateExpired) {
+		t.Errorf("만료된 state ClaimForExchange err = %v, want ErrStateExpired", err)
+	}
+}
+
+func TestOAuthStateWithoutUser(t *testing.T) {
+	s := newTestStateStore(t)
+
+	// 사용자 없이 발급된 이전 state는 누구도 교환할 수 없음
+	issued, _ := s.Issue("tiktok", StateOptions{})
+	if _, err := s.Consume("tiktok", issued.State); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if _, err := s.ClaimForExchange("tiktok", issued.State, ""); !errors.Is(err, ErrStateNotOwned) {
+		t.Errorf("err = %v, want ErrStateNotOwned", err)
+	}
+}
+
+func TestOAuthStateUnknown(t *testing.T) {
+	s := newTestStateStore(t)
+
+	for _, value := range []string{"", "not-issued"} {
+		if _, err := s.Consume("tiktok", value); !errors.Is(err, ErrStateNotFound) {
+			t.Errorf("Consume(%q) err = %v, want ErrStateNotFound", value, err)
+		}
+		if _, err := s.ClaimForExchange("tiktok", value, "user-1"); !errors.Is(err, ErrStateNotFound) {
+			t.Errorf("ClaimForExchange(%q) err = %v, want ErrStateNotFound", value, err)
+		}
+	}
+}
+
+func TestOAuthStateConcurrentConsume(t *testing.T) {
+	s := newTestStateStore(t)
+	issued, _ := s.Issue("tiktok", StateOptions{UserID: "user-1"})
+
+	// 같은 state로 동시에 콜백이 와도 하나만 성공
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Consume("tiktok", issued.State); err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if successes != 1 {
+		t.Errorf("성공 %d회, want 1", successes)
+	}
+}
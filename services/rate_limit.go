@@ -0,0 +1,60 @@
+package services
+
+import (
+	"log"
+
+	"adfit-oauth/config"
+	"adfit-oauth/ratelimit"
+)
+
+// 설정 파일이 없을 때 예산
+var defaultRateLimit = config.RateLimitConfig{
+	RequestsPerMinute: 60,
+	Burst:             10,
+	OAuth:             config.RateLimitBudget{RequestsPerMinute: 20, Burst: 5},
+	Stats:             config.RateLimitBudget{RequestsPerMinute: 6, Burst: 2},
+}
+
+// 현재 버킷 저장소 (재로드 시 backend가 같으면 기존 버킷 유지)
+var rateLimitBackend string
+
+// InitRateLimiter security.rate_limit으로 전역 Limiter 초기화 (enabled: false면 비활성화)
+func InitRateLimiter() error {
+	return ApplyRateLimit(config.Current())
+}
+
+// ApplyRateLimit 설정 반영 (시작 시 + 설정 재로드 시, backend가 같으면 예산만 교체)
+func ApplyRateLimit(appConfig *config.AppConfig) error {
+	cfg := defaultRateLimit
+	if appConfig != nil {
+		cfg = appConfig.Security.RateLimit
+	}
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		ratelimit.SetDefault(nil)
+		log.Printf("⚠️ 요청 제한 비활성화")
+		return nil
+	}
+
+	if limiter := ratelimit.Default(); limiter != nil && cfg.Backend == rateLimitBackend {
+		limiter.SetBudgets(RateLimitBudgets(cfg))
+	} else {
+		store, err := ratelimit.NewStore(cfg.Backend)
+		if err != nil {
+			return err
+		}
+		ratelimit.SetDefault(ratelimit.NewLimiter(store, RateLimitBudgets(cfg)))
+		rateLimitBackend = cfg.Backend
+	}
+	log.Printf("✅ 요청 제한 설정 완료 (기본 %d/분, OAuth %d/분, 통계 %d/분)",
+		cfg.RequestsPerMinute, cfg.OAuth.RequestsPerMinute, cfg.Stats.RequestsPerMinute)
+	return nil
+}
+
+// RateLimitBudgets 설정 → 예산 이름별 Limit
+func RateLimitBudgets(cfg config.RateLimitConfig) map[string]ratelimit.Limit {
+	return map[string]ratelimit.Limit{
+		ratelimit.BudgetDefault: {RequestsPerMinute: cfg.RequestsPerMinute, Burst: cfg.Burst},
+		ratelimit.BudgetOAuth:   {RequestsPerMinute: cfg.OAuth.RequestsPerMinute, Burst: cfg.OAuth.Burst},
+		ratelimit.BudgetStats:   {RequestsPerMinute: cfg.Stats.RequestsPerMinute, Burst: cfg.Stats.Burst},
+	}
+}
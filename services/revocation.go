@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// RevocationService 로그아웃/연결 해제 시 플랫폼 권한 취소
+type RevocationService struct {
+	DB          *gorm.DB
+	Providers   *providers.Registry
+	MaxAttempts int           // 재시도 포함 최대 시도 횟수
+	BaseBackoff time.Duration // 재시도 간격 (지수 증가)
+}
+
+func NewRevocationService(db *gorm.DB, registry *providers.Registry) *RevocationService {
+	return &RevocationService{
+		DB:          db,
+		Providers:   registry,
+		MaxAttempts: 6,
+		BaseBackoff: 5 * time.Minute,
+	}
+}
+
+// WithDB 다른 DB 핸들(트랜잭션)을 사용하는 복사본
+func (s *RevocationService) WithDB(db *gorm.DB) *RevocationService {
+	copied := *s
+	copied.DB = db
+	return &copied
+}
+
+// RevokeResult 권한 취소 결과
+type RevokeResult struct {
+	Disconnected int `json:"disconnected"`   // 삭제된 로컬 토큰 수
+	Revoked      int `json:"revoked"`        // 플랫폼 권한 취소 성공
+	Pending      int `json:"revoke_pending"` // 실패 → 재시도 예정
+	Unsupported  int `json:"revoke_unsupported"`
+}
+
+// Disconnect 플랫폼 권한을 취소하고 로컬 토큰 삭제
+//
+// 권한 취소에 실패해도 로컬 토큰은 삭제하고, 취소할 토큰은 재시도 대기열에 저장합니다.
+// localOnly면 플랫폼 API를 호출하지 않고 로컬 토큰만 삭제합니다.
+func (s *RevocationService) Disconnect(ctx context.Context, tokens []models.UserToken, localOnly bool) (*RevokeResult, error) {
+	result := &RevokeResult{}
+
+	for i := range tokens {
+		t := &tokens[i]
+
+		if !localOnly {
+			s.revoke(ctx, t, result)
+		}
+
+		if err := s.DB.Delete(t).Error; err != nil {
+			return result, err
+		}
+		result.Disconnected++
+	}
+
+	return result, nil
+}
+
+func (s *RevocationService) revoke(ctx context.Context, t *models.UserToken, result *RevokeResult) {
+	provider, ok := s.Providers.Get(t.Platform)
+	if !ok {
+		result.Unsupported++
+		return
+	}
+
+	token := revocationToken(t)
+	err := provider.Revoke(ctx, token)
+	switch {
+	case err == nil:
+		result.Revoked++
+		return
+	case errors.Is(err, providers.ErrRevokeUnsupported):
+		result.Unsupported++
+		return
+	}
+
+	log.Printf("⚠️ %s 권한 취소 실패, 재시도 예정 (user=%s): %v", t.Platform, t.UserID, err)
+	pending := models.PendingRevocation{
+		UserID:        t.UserID,
+		Platform:      t.Platform,
+		AccountID:     t.PlatformAccountID(),
+		Token:         token,
+		Attempts:      1,
+		LastError:     err.Error(),
+		NextAttemptAt: time.Now().Add(s.BaseBackoff),
+	}
+	if err := s.DB.Create(&pending).Error; err != nil {
+		log.Printf("❌ 권한 취소 재시도 저장 실패: %v", err)
+	}
+	result.Pending++
+}
+
+// RetryPending 재시도 시간이 된 권한 취소 재실행
+func (s *RevocationService) RetryPending(ctx context.Context) (revoked, failed int, err error) {
+	var pending []models.PendingRevocation
+	err = s.DB.Where("gave_up_at IS NULL AND next_attempt_at <= ?", time.Now()).Find(&pending).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i := range pending {
+		p := &pending[i]
+		provider, ok := s.Providers.Get(p.Platform)
+
+		var revokeErr error
+		if ok {
+			revokeErr = provider.Revoke(ctx, p.Token)
+		} else {
+			revokeErr = errors.New("unknown provider: " + p.Platform)
+		}
+
+		// 성공했거나 더 이상 취소할 수 없는 경우 대기열에서 제거
+		if revokeErr == nil || errors.Is(revokeErr, providers.ErrRevokeUnsupported) {
+			s.DB.Delete(p)
+			revoked++
+			continue
+		}
+
+		failed++
+		p.Attempts++
+		p.LastError = revokeErr.Error()
+		p.NextAttemptAt = time.Now().Add(s.BaseBackoff * time.Duration(1<<p.Attempts))
+		if p.Attempts >= s.MaxAttempts {
+			now := time.Now()
+			p.GaveUpAt = &now
+			log.Printf("❌ %s 권한 취소 %d회 실패, 재시도 중단 (user=%s): %v", p.Platform, p.Attempts, p.UserID, revokeErr)
+		}
+		s.DB.Model(p).Select("attempts", "last_error", "next_attempt_at", "gave_up_at").Updates(p)
+	}
+
+	return revoked, failed, nil
+}
+
+// 플랫폼별 취소할 토큰 (Google은 refresh token을 취소해야 전체 권한이 해제됨)
+func revocationToken(t *models.UserToken) string {
+	if t.Platform == "youtube" && t.RefreshToken != "" {
+		return t.RefreshToken
+	}
+	return t.AccessToken
+}
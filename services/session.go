@@ -0,0 +1,111 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"adfit-oauth/config"
+	"adfit-oauth/session"
+)
+
+// InitSessions 설정의 서명 키로 전역 세션 Manager 초기화
+//
+// security.session.keys가 없으면 JWT_SECRET을 "default" 키로 사용하고,
+// 그것도 없으면 임시 키를 만들어 사용합니다 (서버 재시작 시 모든 세션 만료).
+func InitSessions(db *gorm.DB) error {
+	var cfg config.SessionConfig
+	var legacySecret, legacyTTL string
+	appConfig := config.Current()
+	if appConfig != nil {
+		cfg = appConfig.Security.Session
+		legacySecret = appConfig.Security.JWTSecret
+		legacyTTL = appConfig.Security.TokenTTL
+	}
+	if legacySecret == "" {
+		legacySecret = os.Getenv("JWT_SECRET")
+	}
+
+	opts := session.Options{
+		Issuer:      cfg.Issuer,
+		Audience:    cfg.Audience,
+		ActiveKeyID: cfg.ActiveKeyID,
+		Keys:        map[string][]byte{},
+		Algorithms:  cfg.Algorithms,
+		ClockSkew:   30 * time.Second,
+	}
+	if opts.Issuer == "" {
+		opts.Issuer = "adfit-oauth"
+	}
+	if opts.Audience == "" {
+		opts.Audience = "adfit-app"
+	}
+
+	accessTTL := cfg.AccessTTL
+	if accessTTL == "" {
+		accessTTL = legacyTTL
+	}
+	var err error
+	if opts.AccessTTL, err = parseOptionalDuration(accessTTL); err != nil {
+		return fmt.Errorf("security.session.access_ttl: %v", err)
+	}
+	if opts.RefreshTTL, err = parseOptionalDuration(cfg.RefreshTTL); err != nil {
+		return fmt.Errorf("security.session.refresh_ttl: %v", err)
+	}
+	if cfg.ClockSkew != "" {
+		if opts.ClockSkew, err = time.ParseDuration(cfg.ClockSkew); err != nil {
+			return fmt.Errorf("security.session.clock_skew: %v", err)
+		}
+	}
+
+	for kid, key := range cfg.Keys {
+		opts.Keys[kid] = []byte(key)
+	}
+	switch {
+	case len(opts.Keys) > 0:
+	case legacySecret != "":
+		opts.Keys["default"] = []byte(legacySecret)
+		opts.ActiveKeyID = "default"
+	default:
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		opts.Keys["ephemeral"] = key
+		opts.ActiveKeyID = "ephemeral"
+		log.Println("⚠️ 세션 서명 키가 설정되지 않음 (임시 키 사용, 재시작 시 모든 세션 만료)")
+	}
+	for kid, key := range opts.Keys {
+		if len(key) < 32 {
+			log.Printf("⚠️ 세션 서명 키 %q가 너무 짧습니다 (%d바이트, 32바이트 이상 권장)", kid, len(key))
+		}
+	}
+
+	manager, err := session.NewManager(db, opts)
+	if err != nil {
+		return err
+	}
+	session.SetDefault(manager)
+	log.Printf("🔑 세션 JWT 활성화 (활성 키: %s, 전체 키: %d개)", opts.ActiveKeyID, len(opts.Keys))
+
+	// Firebase ID token 인증
+	if appConfig != nil && appConfig.Firebase.AuthEnabled && appConfig.Firebase.ProjectID != "" {
+		verifier := session.NewFirebaseVerifier(appConfig.Firebase.ProjectID, appConfig.Firebase.JWKSURL, opts.ClockSkew)
+		session.SetFirebaseVerifier(verifier)
+		log.Printf("🔥 Firebase ID token 인증 활성화 (프로젝트: %s, JWKS: %s)", verifier.ProjectID, verifier.JWKSURL)
+	} else {
+		session.SetFirebaseVerifier(nil)
+	}
+	return nil
+}
+
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
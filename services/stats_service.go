@@ -46,14 +46,15 @@ func NewStatsService() (*StatsService, error) {
 	var err error
 
 	// config가 로드되어 있으면 사용, 없으면 기본값
-	if config.Config != nil {
-		if config.Config.Firebase.CredentialsPath != "" {
+	// (크론 고루틴에서도 호출되므로 재로드와 겹치지 않게 Current()로 읽음)
+	if cfg := config.Current(); cfg != nil {
+		if cfg.Firebase.CredentialsPath != "" {
 			app, err = firebase.NewApp(ctx, &firebase.Config{
-				ProjectID: config.Config.Firebase.ProjectID,
-			}, option.WithCredentialsFile(config.Config.Firebase.CredentialsPath))
+				ProjectID: cfg.Firebase.ProjectID,
+			}, option.WithCredentialsFile(cfg.Firebase.CredentialsPath))
 		} else {
 			app, err = firebase.NewApp(ctx, &firebase.Config{
-				ProjectID: config.Config.Firebase.ProjectID,
+				ProjectID: cfg.Firebase.ProjectID,
 			})
 		}
 	} else {
@@ -77,7 +78,7 @@ func NewStatsService() (*StatsService, error) {
 	var youtubeService *youtube.Service
 	var apiKey string
 	
-	if config.Config != nil {
+	if config.Current() != nil {
 		apiKey = config.GetYouTubeAPIKey()
 	} else {
 		// 환경변수에서 직접 읽기 (하위 호환성)
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"adfit-oauth/config"
+	"adfit-oauth/encryption"
+	"adfit-oauth/models"
+)
+
+// InitTokenEncryption 설정의 마스터 키로 전역 Keyring 초기화
+func InitTokenEncryption() error {
+	appConfig := config.Current()
+	if appConfig == nil || len(appConfig.Security.Encryption.Keys) == 0 {
+		encryption.SetDefault(nil)
+		log.Println("⚠️ 토큰 암호화 키가 설정되지 않음 (평문 저장)")
+		return nil
+	}
+
+	cfg := appConfig.Security.Encryption
+	keys, err := encryption.ParseKeys(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	keyring, err := encryption.NewKeyring(cfg.ActiveKeyID, keys)
+	if err != nil {
+		return err
+	}
+
+	encryption.SetDefault(keyring)
+	log.Printf("🔐 토큰 암호화 활성화 (활성 키: %s, 전체 키: %d개)", keyring.ActiveKeyID(), len(keys))
+	return nil
+}
+
+// ReencryptTokens 활성 키가 아닌 키(또는 평문)로 저장된 토큰을 활성 키로 다시 암호화
+// (연결 계정 토큰 + 권한 취소 대기열)
+//
+// plaintextOnly가 true이면 평문 토큰만 암호화합니다 (최초 시작 시 마이그레이션).
+func ReencryptTokens(db *gorm.DB, plaintextOnly bool) (int, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return 0, fmt.Errorf("토큰 암호화 키가 설정되지 않았습니다")
+	}
+
+	stale := func(model interface{}) *gorm.DB {
+		if plaintextOnly {
+			return db.Model(model).Where("key_id = ? OR key_id IS NULL", "")
+		}
+		return db.Model(model).Where("key_id <> ? OR key_id IS NULL", keyring.ActiveKeyID())
+	}
+
+	var tokens []models.UserToken
+	count := 0
+	err := stale(&models.UserToken{}).FindInBatches(&tokens, 100, func(tx *gorm.DB, batch int) error {
+		for i := range tokens {
+			// 조회 시 복호화된 값을 다시 쓰면 활성 키로 암호화됨 (updated_at은 유지)
+			tokens[i].KeyID = keyring.ActiveKeyID()
+			err := db.Model(&tokens[i]).
+				Select("access_token", "refresh_token", "key_id").
+				UpdateColumns(&tokens[i]).Error
+			if err != nil {
+				return fmt.Errorf("토큰 %d 재암호화 실패: %v", tokens[i].ID, err)
+			}
+			count++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return count, err
+	}
+
+	// 키를 폐기하면 대기열을 읽지 못해 재시도가 모두 멈추므로 함께 교체
+	var pending []models.PendingRevocation
+	err = stale(&models.PendingRevocation{}).FindInBatches(&pending, 100, func(tx *gorm.DB, batch int) error {
+		for i := range pending {
+			pending[i].KeyID = keyring.ActiveKeyID()
+			err := db.Model(&pending[i]).
+				Select("token", "key_id").
+				UpdateColumns(&pending[i]).Error
+			if err != nil {
+				return fmt.Errorf("권한 취소 대기 %d 재암호화 실패: %v", pending[i].ID, err)
+			}
+			count++
+		}
+		return nil
+	}).Error
+
+	return count, err
+}
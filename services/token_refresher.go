@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// TokenRefresher 만료가 가까운 연결 계정 토큰을 백그라운드에서 갱신
+type TokenRefresher struct {
+	DB          *gorm.DB
+	Providers   *providers.Registry
+	Window      time.Duration // 기본 갱신 시작 시점 (만료 전)
+	MaxJitter   time.Duration // 토큰별 요청 전 랜덤 대기 (플랫폼 API 요청 분산)
+	MaxAttempts int           // 일시적 오류 재시도 횟수
+	BaseBackoff time.Duration // 재시도 대기 (지수 증가)
+	// MaxPerRun 한 번 실행에서 처리할 최대 토큰 수 (만료가 가까운 순, 나머지는 다음 실행, 0이면 제한 없음)
+	// 토큰마다 지터/백오프가 있으므로 크론 주기 안에 끝나도록 제한
+	MaxPerRun int
+}
+
+func NewTokenRefresher(db *gorm.DB, registry *providers.Registry) *TokenRefresher {
+	return &TokenRefresher{
+		DB:          db,
+		Providers:   registry,
+		Window:      time.Hour,
+		MaxJitter:   5 * time.Second,
+		MaxAttempts: 3,
+		BaseBackoff: 2 * time.Second,
+		MaxPerRun:   100,
+	}
+}
+
+// RefreshResult 갱신 실행 결과
+type RefreshResult struct {
+	Refreshed      int
+	NeedsReconnect int
+	Failed         int
+	Deferred       int // MaxPerRun을 넘어 다음 실행으로 미룬 토큰
+}
+
+// RefreshExpiring 만료 예정 토큰 갱신
+func (r *TokenRefresher) RefreshExpiring(ctx context.Context) (*RefreshResult, error) {
+	result := &RefreshResult{}
+	processed := 0
+
+	for _, name := range r.Providers.Names() {
+		provider, _ := r.Providers.Get(name)
+
+		window := r.Window
+		if w, ok := provider.(providers.RefreshWindower); ok {
+			window = w.RefreshWindow()
+		}
+
+		var tokens []models.UserToken
+		err := r.DB.Where("platform = ? AND status <> ? AND refresh_token <> ? AND expires_at < ?",
+			name, models.TokenStatusNeedsReconnect, "", time.Now().Add(window)).
+			Order("expires_at").
+			Find(&tokens).Error
+		if err != nil {
+			return result, err
+		}
+
+		for i := range tokens {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if r.MaxPerRun > 0 && processed >= r.MaxPerRun {
+				result.Deferred += len(tokens) - i
+				break
+			}
+			r.refreshOne(ctx, provider, &tokens[i], window, result)
+			processed++
+		}
+	}
+
+	return result, nil
+}
+
+// 토큰 하나 갱신 (지터 + 지수 백오프)
+func (r *TokenRefresher) refreshOne(ctx context.Context, provider providers.OAuthProvider, userToken *models.UserToken, window time.Duration, result *RefreshResult) {
+	if r.MaxJitter > 0 {
+		sleep(ctx, time.Duration(rand.Int63n(int64(r.MaxJitter))))
+	}
+
+	// API 요청 중 같은 토큰이 갱신되지 않도록 잠그고, 그 사이 이미 갱신되었으면 건너뜀
+	unlock := lockToken(userToken.ID)
+	defer unlock()
+	if err := r.DB.First(userToken, userToken.ID).Error; err != nil {
+		result.Failed++
+		return
+	}
+	if userToken.ExpiresAt.After(time.Now().Add(window)) {
+		return
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < r.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			sleep(ctx, r.BaseBackoff*time.Duration(1<<(attempt-1)))
+		}
+
+		token, err := provider.Refresh(ctx, userToken.RefreshToken)
+		if err == nil {
+			if err := SaveRefreshedToken(r.DB, userToken, token); err != nil {
+				log.Printf("❌ 토큰 저장 실패 (%s, user=%s): %v", provider.Name(), userToken.UserID, err)
+				result.Failed++
+				return
+			}
+			result.Refreshed++
+			return
+		}
+
+		// refresh token 거부 → 재시도하지 않고 재연결 필요로 표시
+		if providers.IsInvalidGrant(err) {
+			log.Printf("⚠️ refresh token 거부됨, 재연결 필요 (%s, user=%s): %v", provider.Name(), userToken.UserID, err)
+			if err := MarkNeedsReconnect(r.DB, userToken, err); err != nil {
+				log.Printf("❌ 토큰 상태 저장 실패: %v", err)
+			}
+			result.NeedsReconnect++
+			return
+		}
+
+		lastErr = err
+	}
+
+	log.Printf("❌ 토큰 갱신 실패 (%s, user=%s, %d회 시도): %v", provider.Name(), userToken.UserID, r.MaxAttempts, lastErr)
+	r.DB.Model(userToken).Update("last_error", lastErr.Error())
+	result.Failed++
+}
+
+// ctx 취소 시 즉시 반환하는 sleep
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
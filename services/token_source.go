@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// 만료 직전 토큰은 미리 갱신 (요청 도중 만료 방지)
+const tokenExpiryDelta = 30 * time.Second
+
+// 같은 토큰의 동시 갱신 방지 (UserToken.ID별 잠금)
+var tokenLocks sync.Map
+
+func lockToken(id uint) func() {
+	v, _ := tokenLocks.LoadOrStore(id, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// DBTokenSource 갱신된 토큰을 DB에 다시 저장하는 oauth2.TokenSource
+//
+// oauth2.Config.Client()는 만료된 토큰을 알아서 갱신하지만 새 토큰을 버리기 때문에
+// 다음 요청마다 다시 갱신하게 되고, Google이 refresh token을 교체하면 기존 값이 무효가 됩니다.
+// 이 TokenSource는 갱신 전에 DB에서 최신 토큰을 다시 읽고(다른 요청이 이미 갱신했을 수 있음),
+// 갱신 결과(access token, 만료 시간, refresh token)를 한 번에 저장합니다.
+type DBTokenSource struct {
+	ctx     context.Context
+	db      *gorm.DB
+	config  *oauth2.Config
+	tokenID uint
+}
+
+func NewDBTokenSource(ctx context.Context, db *gorm.DB, config *oauth2.Config, userToken *models.UserToken) *DBTokenSource {
+	return &DBTokenSource{ctx: ctx, db: db, config: config, tokenID: userToken.ID}
+}
+
+// Token 유효한 토큰 반환 (만료되었으면 갱신 후 저장)
+func (s *DBTokenSource) Token() (*oauth2.Token, error) {
+	unlock := lockToken(s.tokenID)
+	defer unlock()
+
+	var userToken models.UserToken
+	if err := s.db.First(&userToken, s.tokenID).Error; err != nil {
+		return nil, fmt.Errorf("토큰 조회 실패: %v", err)
+	}
+
+	current := &oauth2.Token{
+		AccessToken:  userToken.AccessToken,
+		RefreshToken: userToken.RefreshToken,
+		TokenType:    "Bearer",
+		Expiry:       userToken.ExpiresAt,
+	}
+	if userToken.ExpiresAt.After(time.Now().Add(tokenExpiryDelta)) {
+		return current, nil
+	}
+	if userToken.RefreshToken == "" {
+		return nil, errors.New("refresh token이 없어 토큰을 갱신할 수 없습니다")
+	}
+
+	// 만료 시간을 과거로 설정해서 강제 갱신
+	current.Expiry = time.Now().Add(-time.Minute)
+	newToken, err := s.config.TokenSource(s.ctx, current).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+			MarkNeedsReconnect(s.db, &userToken, err)
+		}
+		return nil, err
+	}
+
+	refreshed := &providers.Token{
+		AccessToken:  newToken.AccessToken,
+		RefreshToken: newToken.RefreshToken,
+		ExpiresAt:    newToken.Expiry,
+	}
+	if err := SaveRefreshedToken(s.db, &userToken, refreshed); err != nil {
+		return nil, fmt.Errorf("갱신된 토큰 저장 실패: %v", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken:  userToken.AccessToken,
+		RefreshToken: userToken.RefreshToken,
+		TokenType:    "Bearer",
+		Expiry:       userToken.ExpiresAt,
+	}, nil
+}
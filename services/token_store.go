@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// SaveRefreshedToken 갱신된 토큰 저장 (새 refresh token이 없으면 기존 값 유지)
+func SaveRefreshedToken(db *gorm.DB, userToken *models.UserToken, token *providers.Token) error {
+	userToken.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		userToken.RefreshToken = token.RefreshToken
+	}
+	if token.Scope != "" {
+		userToken.Scope = token.Scope
+	}
+	userToken.ExpiresAt = token.ExpiresAt
+	userToken.Status = models.TokenStatusActive
+	userToken.LastError = ""
+	userToken.UpdatedAt = time.Now()
+	return db.Save(userToken).Error
+}
+
+// ErrTokenSave 플랫폼 갱신은 성공했지만 DB 저장 실패
+var ErrTokenSave = errors.New("갱신된 토큰 저장 실패")
+
+// RefreshTokenLocked 토큰 하나를 즉시 갱신 (수동 갱신 API용)
+//
+// 백그라운드 갱신/DBTokenSource와 같은 토큰별 잠금을 잡고 DB에서 다시 읽은 refresh token으로 갱신합니다.
+// TikTok처럼 갱신할 때마다 refresh token을 교체하는 플랫폼에서 잠금 없이 동시에 갱신하면
+// 한쪽이 이미 교체된 값을 보내 invalid_grant를 받고 재연결 필요로 잘못 표시됩니다.
+func RefreshTokenLocked(ctx context.Context, db *gorm.DB, provider providers.OAuthProvider, userToken *models.UserToken) error {
+	unlock := lockToken(userToken.ID)
+	defer unlock()
+	if err := db.First(userToken, userToken.ID).Error; err != nil {
+		return fmt.Errorf("토큰 조회 실패: %v", err)
+	}
+
+	token, err := provider.Refresh(ctx, userToken.RefreshToken)
+	if err != nil {
+		if providers.IsInvalidGrant(err) {
+			MarkNeedsReconnect(db, userToken, err)
+		}
+		return err
+	}
+	if err := SaveRefreshedToken(db, userToken, token); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenSave, err)
+	}
+	return nil
+}
+
+// MarkNeedsReconnect 플랫폼이 refresh token을 거부한 토큰 표시
+func MarkNeedsReconnect(db *gorm.DB, userToken *models.UserToken, cause error) error {
+	userToken.Status = models.TokenStatusNeedsReconnect
+	userToken.LastError = cause.Error()
+	return db.Model(userToken).Updates(map[string]interface{}{
+		"status":     userToken.Status,
+		"last_error": userToken.LastError,
+	}).Error
+}
+
+// ErrAccountLinked 다른 사용자에게 이미 연결된 플랫폼 계정 (idx_platform_account_live 위반)
+var ErrAccountLinked = errors.New("account already linked to another user")
+
+// UpsertUserToken 연결 계정 토큰 저장 (user_id + platform + account_id 고유 인덱스 기준 UPSERT)
+//
+// 같은 계정으로 콜백이 동시에 와도 행은 하나만 남습니다. 새 refresh token이 비어 있으면 기존 값을 유지하고,
+// 다른 사용자에게 연결된 계정이면 ErrAccountLinked를 반환합니다.
+func UpsertUserToken(db *gorm.DB, userToken *models.UserToken) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "platform"}, {Name: "account_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL AND account_id <> ''"}}},
+		DoUpdates: append(clause.AssignmentColumns([]string{
+			"access_token", "token_type", "expires_at", "scope", "open_id", "channel_id", "ig_user_id",
+			"status", "last_error", "key_id", "updated_at",
+		}), clause.Assignment{
+			Column: clause.Column{Name: "refresh_token"},
+			Value:  gorm.Expr("CASE WHEN excluded.refresh_token = '' THEN user_tokens.refresh_token ELSE excluded.refresh_token END"),
+		}),
+	}).Create(userToken).Error
+	if isDuplicateKey(db, err) {
+		return ErrAccountLinked
+	}
+	return err
+}
+
+// 고유 인덱스 위반 여부 (gorm.Config.TranslateError 설정과 관계없이)
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// PrepareUserTokens 고유 인덱스를 만들기 전에 이전 데이터 정리 (AutoMigrate 전에 호출)
+//
+// 계정 ID가 비어 있는 이전 토큰의 AccountID를 채우고, 같은 플랫폼 계정에 연결된 토큰이 여럿이면
+// 가장 최근 것만 남기고 연결 해제(soft delete)한 뒤, 고유하지 않던 예전 인덱스를 삭제합니다.
+func PrepareUserTokens(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&models.UserToken{}) {
+		return nil
+	}
+	for _, column := range []string{"AccountID", "IGUserID"} {
+		if !m.HasColumn(&models.UserToken{}, column) {
+			if err := m.AddColumn(&models.UserToken{}, column); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := BackfillAccountIDs(db); err != nil {
+		return err
+	}
+	err := db.Exec(`UPDATE user_tokens SET deleted_at = ?
+		WHERE deleted_at IS NULL AND account_id <> '' AND id NOT IN (
+			SELECT MAX(id) FROM user_tokens WHERE deleted_at IS NULL AND account_id <> '' GROUP BY platform, account_id
+		)`, time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	if m.HasIndex(&models.UserToken{}, "idx_user_platform_account") {
+		return m.DropIndex(&models.UserToken{}, "idx_user_platform_account")
+	}
+	return nil
+}
+
+// BackfillAccountIDs 계정 ID 컬럼 추가 이전 토큰의 AccountID 채우기
+func BackfillAccountIDs(db *gorm.DB) (int64, error) {
+	result := db.Exec(`UPDATE user_tokens
+		SET account_id = COALESCE(NULLIF(open_id, ''), NULLIF(channel_id, ''), NULLIF(ig_user_id, ''), '')
+		WHERE account_id IS NULL OR account_id = ''`)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"adfit-oauth/models"
+	"adfit-oauth/providers"
+)
+
+// 갱신할 때마다 refresh token을 교체하는 가짜 플랫폼 (TikTok과 같은 동작)
+type rotatingProvider struct {
+	mu      sync.Mutex
+	current string
+	calls   int
+}
+
+func (p *rotatingProvider) Name() string                               { return "tiktok" }
+func (p *rotatingProvider) AuthURL(state, codeChallenge string) string { return "" }
+func (p *rotatingProvider) Revoke(ctx context.Context, token string) error {
+	return providers.ErrRevokeUnsupported
+}
+func (p *rotatingProvider) Exchange(ctx context.Context, code, codeVerifier string) (*providers.Token, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (p *rotatingProvider) Profile(ctx context.Context, accessToken string) (*providers.Profile, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (p *rotatingProvider) Refresh(ctx context.Context, refreshToken string) (*providers.Token, error) {
+	p.mu.Lock()
+	current := p.current
+	p.mu.Unlock()
+
+	// 요청이 오가는 동안 다른 갱신이 끼어들 수 있도록 지연
+	time.Sleep(20 * time.Millisecond)
+	if refreshToken != current {
+		return nil, &providers.Error{Provider: "TikTok", Code: "invalid_grant", Description: "refresh token already rotated"}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	p.current = fmt.Sprintf("refresh-%d", p.calls)
+	return &providers.Token{
+		AccessToken:  fmt.Sprintf("access-%d", p.calls),
+		RefreshToken: p.current,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
+	}, nil
+}
+
+// 메모리 DB (연결 하나만 사용해야 같은 메모리 DB를 봄)
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("DB 열기 실패: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func newTestTokenDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.UserToken{}); err != nil {
+		t.Fatalf("마이그레이션 실패: %v", err)
+	}
+	return db
+}
+
+func TestRefreshTokenLockedConcurrent(t *testing.T) {
+	db := newTestTokenDB(t)
+
+	stored := models.UserToken{UserID: "user-1", Platform: "tiktok", AccountID: "open-1",
+		AccessToken: "access-0", RefreshToken: "refresh-0", ExpiresAt: time.Now()}
+	if err := db.Create(&stored).Error; err != nil {
+		t.Fatalf("토큰 저장 실패: %v", err)
+	}
+	provider := &rotatingProvider{current: "refresh-0"}
+
+	// 두 요청이 같은 (이전) 행을 읽은 상태에서 동시에 갱신
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		stale := stored
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = RefreshTokenLocked(context.Background(), db, provider, &stale)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("%d번째 갱신 실패: %v", i+1, err)
+		}
+	}
+	var latest models.UserToken
+	db.First(&latest, stored.ID)
+	if latest.Status != models.TokenStatusActive || latest.RefreshToken != "refresh-2" {
+		t.Errorf("status = %q, refresh token = %q", latest.Status, latest.RefreshToken)
+	}
+}
+
+func TestUpsertUserToken(t *testing.T) {
+	db := newTestTokenDB(t)
+	token := func(userID, access, refresh string) *models.UserToken {
+		return &models.UserToken{UserID: userID, Platform: "tiktok", AccountID: "open-1", OpenID: "open-1",
+			AccessToken: access, RefreshToken: refresh, Status: models.TokenStatusActive}
+	}
+
+	if err := UpsertUserToken(db, token("user-1", "access-1", "refresh-1")); err != nil {
+		t.Fatalf("UpsertUserToken: %v", err)
+	}
+	// 같은 계정 재연결: 행은 하나, 새 refresh token이 없으면 기존 값 유지
+	if err := UpsertUserToken(db, token("user-1", "access-2", "")); err != nil {
+		t.Fatalf("UpsertUserToken: %v", err)
+	}
+	var rows []models.UserToken
+	db.Where("user_id = ?", "user-1").Find(&rows)
+	if len(rows) != 1 || rows[0].AccessToken != "access-2" || rows[0].RefreshToken != "refresh-1" {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	// 다른 사용자에게 연결된 계정은 DB가 거부
+	if err := UpsertUserToken(db, token("user-2", "access-3", "refresh-3")); !errors.Is(err, ErrAccountLinked) {
+		t.Fatalf("다른 사용자 err = %v, want ErrAccountLinked", err)
+	}
+
+	// 연결 해제(soft delete)된 계정은 다른 사용자가 연결 가능
+	db.Delete(&rows[0])
+	if err := UpsertUserToken(db, token("user-2", "access-3", "refresh-3")); err != nil {
+		t.Fatalf("연결 해제 후 UpsertUserToken: %v", err)
+	}
+}
+
+func TestUpsertUserTokenConcurrent(t *testing.T) {
+	db := newTestTokenDB(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := UpsertUserToken(db, &models.UserToken{UserID: "user-1", Platform: "youtube", AccountID: "channel-1",
+				AccessToken: fmt.Sprintf("access-%d", i), Status: models.TokenStatusActive})
+			if err != nil {
+				t.Errorf("UpsertUserToken: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var count int64
+	db.Model(&models.UserToken{}).Where("platform = ? AND account_id = ?", "youtube", "channel-1").Count(&count)
+	if count != 1 {
+		t.Errorf("행 %d개, want 1", count)
+	}
+}
+
+func TestPrepareUserTokens(t *testing.T) {
+	db := newTestDB(t)
+
+	// 계정 ID 컬럼과 고유 인덱스가 없던 이전 스키마 + 중복 연결
+	err := db.Exec(`CREATE TABLE user_tokens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME,
+		user_id TEXT NOT NULL, platform TEXT NOT NULL DEFAULT 'tiktok', access_token TEXT NOT NULL,
+		refresh_token TEXT, token_type TEXT, expires_at DATETIME, scope TEXT, open_id TEXT, channel_id TEXT)`).Error
+	if err != nil {
+		t.Fatalf("이전 테이블 생성 실패: %v", err)
+	}
+	db.Exec(`CREATE INDEX idx_user_platform_account ON user_tokens(user_id, platform)`)
+	for _, row := range [][]string{
+		{"user-1", "tiktok", "open-1", ""},
+		{"user-1", "tiktok", "open-1", ""},
+		{"user-2", "tiktok", "open-1", ""},
+		{"user-1", "youtube", "", "channel-1"},
+	} {
+		db.Exec(`INSERT INTO user_tokens (user_id, platform, access_token, open_id, channel_id) VALUES (?, ?, 'a', ?, ?)`,
+			row[0], row[1], row[2], row[3])
+	}
+
+	if err := PrepareUserTokens(db); err != nil {
+		t.Fatalf("PrepareUserTokens: %v", err)
+	}
+	if err := db.AutoMigrate(&models.UserToken{}); err != nil {
+		t.Fatalf("고유 인덱스 생성 실패: %v", err)
+	}
+
+	var live []models.UserToken
+	db.Order("id").Find(&live)
+	// 같은 TikTok 계정은 가장 최근 연결만, 계정 ID는 플랫폼별 컬럼에서 채움
+	if len(live) != 2 || live[0].UserID != "user-2" || live[0].AccountID != "open-1" || live[1].AccountID != "channel-1" {
+		t.Errorf("남은 연결 = %+v", live)
+	}
+	if db.Migrator().HasIndex(&models.UserToken{}, "idx_user_platform_account") {
+		t.Error("예전 인덱스가 남아 있음")
+	}
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultFirebaseJWKSURL Firebase ID token 서명 공개키 (JWKS)
+const DefaultFirebaseJWKSURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+// unknown kid로 JWKS를 다시 받는 최소 간격 (위조 토큰으로 요청 폭주 방지)
+const jwksMinRefreshInterval = time.Minute
+
+var ErrFirebaseUnavailable = errors.New("firebase auth is not configured")
+
+// FirebaseClaims Firebase ID token 클레임
+type FirebaseClaims struct {
+	UserID    string `json:"user_id"`
+	Email     string `json:"email,omitempty"`
+	AuthTime  int64  `json:"auth_time"`
+	AdminRole string `json:"admin_role,omitempty"` // 커스텀 클레임 (관리자 권한)
+	jwt.RegisteredClaims
+}
+
+// FirebaseVerifier Firebase ID token 검증 (RS256, Google JWKS)
+//
+// iss = https://securetoken.google.com/<project>, aud = <project>, sub = uid 를 확인합니다.
+// JWKSURL을 로컬 서버로 바꾸면 테스트에서 직접 서명한 토큰을 사용할 수 있습니다.
+type FirebaseVerifier struct {
+	ProjectID  string
+	JWKSURL    string
+	ClockSkew  time.Duration
+	HTTPClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	fetchedAt time.Time
+}
+
+func NewFirebaseVerifier(projectID, jwksURL string, clockSkew time.Duration) *FirebaseVerifier {
+	if jwksURL == "" {
+		jwksURL = DefaultFirebaseJWKSURL
+	}
+	return &FirebaseVerifier{
+		ProjectID:  projectID,
+		JWKSURL:    jwksURL,
+		ClockSkew:  clockSkew,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify ID token 검증 후 클레임 반환
+func (v *FirebaseVerifier) Verify(ctx context.Context, tokenString string) (*FirebaseClaims, error) {
+	claims := &FirebaseClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer("https://securetoken.google.com/"+v.ProjectID),
+		jwt.WithAudience(v.ProjectID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(v.ClockSkew),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" || len(claims.Subject) > 128 {
+		return nil, errors.New("firebase token has invalid sub")
+	}
+	if claims.AuthTime > time.Now().Add(v.ClockSkew).Unix() {
+		return nil, errors.New("firebase token has future auth_time")
+	}
+	return claims, nil
+}
+
+// kid에 해당하는 공개키 (캐시 만료 또는 모르는 kid면 JWKS 다시 조회)
+func (v *FirebaseVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	fresh := time.Now().Before(v.expiresAt)
+	recentlyFetched := time.Since(v.fetchedAt) < jwksMinRefreshInterval
+	v.mu.RUnlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+	if !ok && fresh && recentlyFetched {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		if ok {
+			// JWKS 조회 실패 시 이전 키로 계속 검증
+			return key, nil
+		}
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+var maxAgePattern = regexp.MustCompile(`max-age=(\d+)`)
+
+// JWKS 조회 (Cache-Control max-age 동안 캐시)
+func (v *FirebaseVerifier) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", v.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("JWKS 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS 조회 실패: HTTP %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("JWKS 파싱 실패: %v", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	maxAge := time.Hour
+	if m := maxAgePattern.FindStringSubmatch(resp.Header.Get("Cache-Control")); m != nil {
+		if seconds, err := strconv.Atoi(m[1]); err == nil {
+			maxAge = time.Duration(seconds) * time.Second
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.expiresAt = v.fetchedAt.Add(maxAge)
+	return nil
+}
+
+// 전역 FirebaseVerifier (설정되지 않았으면 Firebase ID token 인증 비활성화)
+var (
+	firebaseMu       sync.RWMutex
+	firebaseVerifier *FirebaseVerifier
+)
+
+// SetFirebaseVerifier 전역 FirebaseVerifier 설정
+func SetFirebaseVerifier(v *FirebaseVerifier) {
+	firebaseMu.Lock()
+	defer firebaseMu.Unlock()
+	firebaseVerifier = v
+}
+
+// Firebase 전역 FirebaseVerifier (nil이면 비활성화)
+func Firebase() *FirebaseVerifier {
+	firebaseMu.RLock()
+	defer firebaseMu.RUnlock()
+	return firebaseVerifier
+}
@@ -0,0 +1,171 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testProjectID = "adfit-test"
+
+// Google JWKS 대신 쓰는 로컬 서버 (조회 횟수 기록)
+type testJWKS struct {
+	keys    map[string]*rsa.PrivateKey
+	fetches int32
+}
+
+func newTestJWKS(t *testing.T, kids ...string) (*testJWKS, *FirebaseVerifier) {
+	t.Helper()
+	j := &testJWKS{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("키 생성 실패: %v", err)
+		}
+		j.keys[kid] = key
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&j.fetches, 1)
+		var keys []map[string]string
+		for kid, key := range j.keys {
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	t.Cleanup(srv.Close)
+
+	return j, NewFirebaseVerifier(testProjectID, srv.URL, time.Minute)
+}
+
+// 유효한 Firebase ID token 클레임
+func validFirebaseClaims() *FirebaseClaims {
+	now := time.Now()
+	return &FirebaseClaims{
+		UserID:   "uid-1",
+		Email:    "user@example.com",
+		AuthTime: now.Add(-time.Minute).Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://securetoken.google.com/" + testProjectID,
+			Audience:  jwt.ClaimStrings{testProjectID},
+			Subject:   "uid-1",
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func (j *testJWKS) sign(t *testing.T, kid string, claims *FirebaseClaims) string {
+	t.Helper()
+	key, ok := j.keys[kid]
+	if !ok {
+		var err error
+		if key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatalf("키 생성 실패: %v", err)
+		}
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("서명 실패: %v", err)
+	}
+	return signed
+}
+
+func TestFirebaseVerify(t *testing.T) {
+	jwks, v := newTestJWKS(t, "key-1")
+
+	claims, err := v.Verify(context.Background(), jwks.sign(t, "key-1", validFirebaseClaims()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "uid-1" || claims.Email != "user@example.com" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	// 캐시 기간 안에서는 JWKS를 다시 받지 않음
+	if _, err := v.Verify(context.Background(), jwks.sign(t, "key-1", validFirebaseClaims())); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if n := atomic.LoadInt32(&jwks.fetches); n != 1 {
+		t.Errorf("JWKS 조회 %d회, want 1", n)
+	}
+}
+
+func TestFirebaseVerifyRejects(t *testing.T) {
+	jwks, v := newTestJWKS(t, "key-1")
+
+	tests := []struct {
+		name   string
+		kid    string
+		mutate func(c *FirebaseClaims)
+	}{
+		{"다른 프로젝트 aud", "key-1", func(c *FirebaseClaims) { c.Audience = jwt.ClaimStrings{"other-project"} }},
+		{"다른 iss", "key-1", func(c *FirebaseClaims) { c.Issuer = "https://securetoken.google.com/other-project" }},
+		{"만료", "key-1", func(c *FirebaseClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }},
+		{"빈 sub", "key-1", func(c *FirebaseClaims) { c.Subject = "" }},
+		{"미래 auth_time", "key-1", func(c *FirebaseClaims) { c.AuthTime = time.Now().Add(time.Hour).Unix() }},
+		{"JWKS에 없는 키로 서명", "key-unknown", func(c *FirebaseClaims) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validFirebaseClaims()
+			tt.mutate(claims)
+			if _, err := v.Verify(context.Background(), jwks.sign(t, tt.kid, claims)); err == nil {
+				t.Error("검증을 통과하면 안 됨")
+			}
+		})
+	}
+}
+
+func TestFirebaseVerifyRejectsHMAC(t *testing.T) {
+	_, v := newTestJWKS(t, "key-1")
+
+	// 공개키를 HMAC 비밀로 쓰는 alg 변조 토큰
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, validFirebaseClaims())
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("서명 실패: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), signed); err == nil {
+		t.Error("HS256 토큰이 통과하면 안 됨")
+	}
+}
+
+func TestFirebaseUnknownKidThrottled(t *testing.T) {
+	jwks, v := newTestJWKS(t, "key-1")
+	if _, err := v.Verify(context.Background(), jwks.sign(t, "key-1", validFirebaseClaims())); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	// 모르는 kid가 반복되어도 최소 간격 안에서는 JWKS를 다시 받지 않음
+	for i := 0; i < 3; i++ {
+		_, err := v.Verify(context.Background(), jwks.sign(t, "key-unknown", validFirebaseClaims()))
+		if !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("err = %v, want ErrUnknownKey", err)
+		}
+	}
+	if n := atomic.LoadInt32(&jwks.fetches); n != 1 {
+		t.Errorf("JWKS 조회 %d회, want 1", n)
+	}
+}
@@ -0,0 +1,386 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+
+	"adfit-oauth/models"
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown signing key")
+	ErrRevoked         = errors.New("session revoked")
+	ErrRefreshInvalid  = errors.New("invalid refresh token")
+	ErrRefreshExpired  = errors.New("refresh token expired")
+	ErrRefreshReused   = errors.New("refresh token reused")
+	ErrRefreshRevoked  = errors.New("refresh token revoked")
+	ErrRefreshNotOwned = errors.New("refresh token not owned by user")
+)
+
+// Claims 세션 access JWT 클레임
+type Claims struct {
+	UserID    string `json:"user_id"`
+	Platform  string `json:"platform,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	OpenID    string `json:"open_id,omitempty"` // 기존 TikTok 클라이언트 호환
+	Scope     string `json:"scope,omitempty"`   // 공백 구분 권한 목록
+	Role      string `json:"role,omitempty"`    // 관리자 권한 (viewer, operator, superadmin)
+	jwt.RegisteredClaims
+}
+
+// DefaultScopes 로그인 세션에 부여하는 권한
+var DefaultScopes = []string{"accounts", "videos", "analytics"}
+
+// Scopes 권한 목록 (scope 클레임이 없는 세션은 DefaultScopes)
+func (c *Claims) Scopes() []string {
+	if c.Scope == "" {
+		return DefaultScopes
+	}
+	return strings.Fields(c.Scope)
+}
+
+// Subject 세션 주체 (사용자 + 로그인에 사용한 플랫폼 계정)
+type Subject struct {
+	UserID    string
+	Platform  string
+	AccountID string
+	Scopes    []string // 비어 있으면 DefaultScopes
+	Role      string   // 관리자 세션
+}
+
+// Tokens 발급된 세션 토큰
+type Tokens struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresIn  time.Duration
+	RefreshExpiresIn time.Duration
+	Claims           *Claims
+}
+
+// Options Manager 설정
+type Options struct {
+	Issuer      string
+	Audience    string
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	ActiveKeyID string
+	Keys        map[string][]byte // kid → HMAC 키 (활성 키로 서명, 모든 키로 검증)
+	Algorithms  []string          // 허용 알고리즘 (HS256/HS384/HS512, 첫 번째로 서명)
+	ClockSkew   time.Duration     // exp/nbf/iat 허용 오차
+}
+
+// Manager 세션 JWT 발급/검증 + refresh token 교체 + jti 거부 목록
+type Manager struct {
+	db      *gorm.DB
+	opts    Options
+	nowFunc func() time.Time
+}
+
+func NewManager(db *gorm.DB, opts Options) (*Manager, error) {
+	if _, ok := opts.Keys[opts.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("활성 서명 키 %q가 키 목록에 없습니다", opts.ActiveKeyID)
+	}
+	for kid, key := range opts.Keys {
+		if kid == "" || len(key) == 0 {
+			return nil, fmt.Errorf("잘못된 서명 키: %q", kid)
+		}
+	}
+	if len(opts.Algorithms) == 0 {
+		opts.Algorithms = []string{jwt.SigningMethodHS256.Alg()}
+	}
+	for _, alg := range opts.Algorithms {
+		if _, ok := jwt.GetSigningMethod(alg).(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("지원하지 않는 서명 알고리즘: %q (HS256/HS384/HS512만 사용 가능)", alg)
+		}
+	}
+	if opts.AccessTTL <= 0 {
+		opts.AccessTTL = 15 * time.Minute
+	}
+	if opts.RefreshTTL <= 0 {
+		opts.RefreshTTL = 30 * 24 * time.Hour
+	}
+	return &Manager{db: db, opts: opts, nowFunc: time.Now}, nil
+}
+
+// Issuer JWT iss
+func (m *Manager) Issuer() string { return m.opts.Issuer }
+
+// Audience JWT aud
+func (m *Manager) Audience() string { return m.opts.Audience }
+
+// Issue 새 로그인 세션 발급 (access JWT + 새 refresh token family)
+func (m *Manager) Issue(subject Subject) (*Tokens, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return m.issue(m.db, subject, familyID)
+}
+
+// Rotate refresh token으로 새 세션 토큰 발급 (사용한 refresh token은 폐기)
+func (m *Manager) Rotate(refreshToken string) (*Tokens, error) {
+	var tokens *Tokens
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var stored models.SessionRefreshToken
+		if err := tx.Where("token_hash = ?", hashToken(refreshToken)).First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshInvalid
+			}
+			return err
+		}
+
+		now := m.nowFunc()
+		switch {
+		case stored.RevokedAt != nil:
+			return ErrRefreshRevoked
+		case stored.UsedAt != nil:
+			return ErrRefreshReused
+		case now.After(stored.ExpiresAt):
+			return ErrRefreshExpired
+		}
+
+		// 동시에 같은 토큰으로 요청한 경우 하나만 성공
+		result := tx.Model(&models.SessionRefreshToken{}).
+			Where("id = ? AND used_at IS NULL", stored.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshReused
+		}
+
+		var err error
+		subject := Subject{
+			UserID:    stored.UserID,
+			Platform:  stored.Platform,
+			AccountID: stored.AccountID,
+			Scopes:    strings.Fields(stored.Scope),
+			Role:      stored.Role,
+		}
+		tokens, err = m.issue(tx, subject, stored.FamilyID)
+		return err
+	})
+	if errors.Is(err, ErrRefreshReused) {
+		// 이미 교체된 토큰 재사용 → 탈취로 보고 같은 family 전체 폐기 (트랜잭션은 롤백되므로 밖에서 실행)
+		m.RevokeRefreshToken(refreshToken)
+	}
+	return tokens, err
+}
+
+// RevokeRefreshToken refresh token이 속한 family 전체 폐기 (로그아웃)
+func (m *Manager) RevokeRefreshToken(refreshToken string) error {
+	var stored models.SessionRefreshToken
+	if err := m.db.Where("token_hash = ?", hashToken(refreshToken)).First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRefreshInvalid
+		}
+		return err
+	}
+	return revokeFamily(m.db, stored.FamilyID, m.nowFunc())
+}
+
+// RevokeOwnRefreshToken userID 소유의 refresh token일 때만 family 전체 폐기 (다른 사용자의 세션은 건드리지 않음)
+func (m *Manager) RevokeOwnRefreshToken(refreshToken, userID string) error {
+	var stored models.SessionRefreshToken
+	if err := m.db.Where("token_hash = ?", hashToken(refreshToken)).First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRefreshInvalid
+		}
+		return err
+	}
+	if userID == "" || stored.UserID != userID {
+		return ErrRefreshNotOwned
+	}
+	return revokeFamily(m.db, stored.FamilyID, m.nowFunc())
+}
+
+// RevokeUser 사용자의 모든 refresh token 폐기
+func (m *Manager) RevokeUser(userID string) error {
+	return m.db.Model(&models.SessionRefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", m.nowFunc()).Error
+}
+
+// Deny access JWT를 만료 시각까지 거부 목록에 추가 (로그아웃)
+func (m *Manager) Deny(claims *Claims) error {
+	if claims.ID == "" {
+		return nil
+	}
+	expiresAt := m.nowFunc().Add(m.opts.AccessTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return m.db.Save(&models.RevokedSession{JTI: claims.ID, UserID: claims.UserID, ExpiresAt: expiresAt}).Error
+}
+
+// IsDenied jti가 거부 목록에 있는지
+func (m *Manager) IsDenied(jti string) (bool, error) {
+	var count int64
+	err := m.db.Model(&models.RevokedSession{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// Verify access JWT 검증
+//
+// 허용 알고리즘만 통과(alg 헤더 변조/none 거부), kid로 서명 키 선택,
+// iss/aud/exp/nbf/iat를 ClockSkew 오차 안에서 검증하고 거부 목록을 확인합니다.
+func (m *Manager) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc,
+		jwt.WithValidMethods(m.opts.Algorithms),
+		jwt.WithIssuer(m.opts.Issuer),
+		jwt.WithAudience(m.opts.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(m.opts.ClockSkew),
+		jwt.WithTimeFunc(m.nowFunc),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := m.IsDenied(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if denied {
+		return nil, ErrRevoked
+	}
+	return claims, nil
+}
+
+// Purge 만료된 refresh token / 거부 목록 삭제
+func (m *Manager) Purge() (int64, error) {
+	now := m.nowFunc()
+	result := m.db.Unscoped().Where("expires_at < ?", now).Delete(&models.SessionRefreshToken{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	denied := m.db.Where("expires_at < ?", now).Delete(&models.RevokedSession{})
+	return result.RowsAffected + denied.RowsAffected, denied.Error
+}
+
+func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.opts.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// access JWT + refresh token 발급 (tx 안에서 refresh token 저장)
+func (m *Manager) issue(tx *gorm.DB, subject Subject, familyID string) (*Tokens, error) {
+	now := m.nowFunc()
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	claims := &Claims{
+		UserID:    subject.UserID,
+		Platform:  subject.Platform,
+		AccountID: subject.AccountID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   subject.UserID,
+			Issuer:    m.opts.Issuer,
+			Audience:  jwt.ClaimStrings{m.opts.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.opts.AccessTTL)),
+		},
+	}
+	if subject.Platform == "tiktok" {
+		claims.OpenID = subject.AccountID
+	}
+	scopes := subject.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	claims.Scope = strings.Join(scopes, " ")
+	claims.Role = subject.Role
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(m.opts.Algorithms[0]), claims)
+	token.Header["kid"] = m.opts.ActiveKeyID
+	accessToken, err := token.SignedString(m.opts.Keys[m.opts.ActiveKeyID])
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	stored := models.SessionRefreshToken{
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		UserID:    subject.UserID,
+		Platform:  subject.Platform,
+		AccountID: subject.AccountID,
+		Scope:     claims.Scope,
+		Role:      subject.Role,
+		ExpiresAt: now.Add(m.opts.RefreshTTL),
+	}
+	if err := tx.Create(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresIn:  m.opts.AccessTTL,
+		RefreshExpiresIn: m.opts.RefreshTTL,
+		Claims:           claims,
+	}, nil
+}
+
+func revokeFamily(tx *gorm.DB, familyID string, now time.Time) error {
+	return tx.Model(&models.SessionRefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// 전역 Manager (미들웨어/핸들러에서 사용)
+var (
+	mu      sync.RWMutex
+	current *Manager
+)
+
+// SetDefault 전역 Manager 설정
+func SetDefault(m *Manager) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = m
+}
+
+// Default 전역 Manager (설정되지 않았으면 nil)
+func Default() *Manager {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
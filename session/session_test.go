@@ -0,0 +1,168 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"adfit-oauth/models"
+)
+
+// 메모리 DB를 쓰는 Manager (연결 하나만 사용해야 같은 메모리 DB를 봄)
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("DB 열기 실패: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.SessionRefreshToken{}, &models.RevokedSession{}); err != nil {
+		t.Fatalf("마이그레이션 실패: %v", err)
+	}
+
+	m, err := NewManager(db, Options{
+		Issuer:      "adfit-oauth",
+		Audience:    "adfit-app",
+		AccessTTL:   15 * time.Minute,
+		RefreshTTL:  time.Hour,
+		ActiveKeyID: "k1",
+		Keys:        map[string][]byte{"k1": []byte("test-signing-key-k1")},
+	})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m
+}
+
+func TestRotate(t *testing.T) {
+	m := newTestManager(t)
+	subject := Subject{UserID: "user-1", Platform: "tiktok", AccountID: "open-1", Scopes: []string{"accounts"}}
+
+	issued, err := m.Issue(subject)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	rotated, err := m.Rotate(issued.RefreshToken)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if rotated.RefreshToken == issued.RefreshToken {
+		t.Error("refresh token이 교체되지 않음")
+	}
+
+	claims, err := m.Verify(rotated.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.UserID != "user-1" || claims.OpenID != "open-1" || claims.Scope != "accounts" {
+		t.Errorf("교체 후 클레임이 유지되지 않음: %+v", claims)
+	}
+}
+
+func TestRotateReuseRevokesFamily(t *testing.T) {
+	m := newTestManager(t)
+
+	issued, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	rotated, err := m.Rotate(issued.RefreshToken)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// 이미 교체된 토큰 재사용 → 탈취로 보고 거부
+	if _, err := m.Rotate(issued.RefreshToken); !errors.Is(err, ErrRefreshReused) {
+		t.Fatalf("재사용 err = %v, want ErrRefreshReused", err)
+	}
+	// 같은 family의 최신 토큰도 폐기됨
+	if _, err := m.Rotate(rotated.RefreshToken); !errors.Is(err, ErrRefreshRevoked) {
+		t.Fatalf("최신 토큰 err = %v, want ErrRefreshRevoked", err)
+	}
+
+	// 다른 로그인(family)은 영향 없음
+	other, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := m.Rotate(other.RefreshToken); err != nil {
+		t.Fatalf("다른 family Rotate: %v", err)
+	}
+}
+
+func TestRotateExpiredAndUnknown(t *testing.T) {
+	m := newTestManager(t)
+
+	issued, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	m.nowFunc = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := m.Rotate(issued.RefreshToken); !errors.Is(err, ErrRefreshExpired) {
+		t.Errorf("만료 err = %v, want ErrRefreshExpired", err)
+	}
+	if _, err := m.Rotate("not-a-refresh-token"); !errors.Is(err, ErrRefreshInvalid) {
+		t.Errorf("모르는 토큰 err = %v, want ErrRefreshInvalid", err)
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	m := newTestManager(t)
+
+	issued, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if err := m.RevokeRefreshToken(issued.RefreshToken); err != nil {
+		t.Fatalf("RevokeRefreshToken: %v", err)
+	}
+	if _, err := m.Rotate(issued.RefreshToken); !errors.Is(err, ErrRefreshRevoked) {
+		t.Errorf("로그아웃 후 err = %v, want ErrRefreshRevoked", err)
+	}
+}
+
+func TestRevokeOwnRefreshToken(t *testing.T) {
+	m := newTestManager(t)
+
+	issued, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	// 다른 사용자는 폐기할 수 없음
+	if err := m.RevokeOwnRefreshToken(issued.RefreshToken, "user-2"); !errors.Is(err, ErrRefreshNotOwned) {
+		t.Fatalf("다른 사용자 err = %v, want ErrRefreshNotOwned", err)
+	}
+	if _, err := m.Rotate(issued.RefreshToken); err != nil {
+		t.Fatalf("거부된 폐기 후 Rotate: %v", err)
+	}
+
+	rotated, _ := m.Issue(Subject{UserID: "user-1"})
+	if err := m.RevokeOwnRefreshToken(rotated.RefreshToken, "user-1"); err != nil {
+		t.Fatalf("RevokeOwnRefreshToken: %v", err)
+	}
+	if _, err := m.Rotate(rotated.RefreshToken); !errors.Is(err, ErrRefreshRevoked) {
+		t.Errorf("로그아웃 후 err = %v, want ErrRefreshRevoked", err)
+	}
+}
+
+func TestVerifyDenied(t *testing.T) {
+	m := newTestManager(t)
+
+	issued, err := m.Issue(Subject{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if err := m.Deny(issued.Claims); err != nil {
+		t.Fatalf("Deny: %v", err)
+	}
+	if _, err := m.Verify(issued.AccessToken); !errors.Is(err, ErrRevoked) {
+		t.Errorf("거부 목록 err = %v, want ErrRevoked", err)
+	}
+}